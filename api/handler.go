@@ -0,0 +1,211 @@
+// Package api implements the rendering endpoint of the MJML API (https://mjml.io/api) on top of mjmlgo.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julez-dev/mjmlgo"
+	"github.com/julez-dev/mjmlgo/component"
+)
+
+// MJMLVersion is the version of the MJML reference implementation the output of mjmlgo is compared against.
+const MJMLVersion = "4.15.3"
+
+const (
+	defaultMaxBodySize = 1 << 20
+	defaultTimeout     = 10 * time.Second
+)
+
+// Handler serves POST /v1/render, accepting {"mjml": "..."} and responding with
+// {"html": "...", "mjml": "...", "mjml_version": "...", "errors": [...]}.
+// The zero value is ready to use.
+type Handler struct {
+	// MaxBodySize limits the size of the request body in bytes, defaults to 1 MiB.
+	MaxBodySize int64
+	// Timeout limits the time spent rendering a single request, defaults to 10 seconds.
+	Timeout time.Duration
+	// MaxConcurrent limits the number of renders running at the same time, including renders which continue
+	// in the background after a timeout. Requests exceeding it are rejected, defaults to runtime.GOMAXPROCS(0).
+	MaxConcurrent int
+	// Options are passed to mjmlgo.RenderMJMLWithStats. Validation defaults to component.ValidationSoft like the MJML API.
+	Options []mjmlgo.Option
+
+	once sync.Once
+	sem  chan struct{}
+}
+
+// semaphore returns the channel holding a value for every running render.
+func (h *Handler) semaphore() chan struct{} {
+	h.once.Do(func() {
+		maxConcurrent := h.MaxConcurrent
+		if maxConcurrent <= 0 {
+			maxConcurrent = runtime.GOMAXPROCS(0)
+		}
+		h.sem = make(chan struct{}, maxConcurrent)
+	})
+
+	return h.sem
+}
+
+type renderRequest struct {
+	MJML string `json:"mjml"`
+}
+
+type renderResponse struct {
+	HTML        string  `json:"html"`
+	MJML        string  `json:"mjml"`
+	MJMLVersion string  `json:"mjml_version"`
+	Errors      []Error `json:"errors"`
+}
+
+type errorResponse struct {
+	Message string  `json:"message"`
+	Errors  []Error `json:"errors,omitempty"`
+}
+
+// Error is a single entry of the errors array in a response.
+type Error struct {
+	Line             int    `json:"line"`
+	Message          string `json:"message"`
+	TagName          string `json:"tagName"`
+	FormattedMessage string `json:"formattedMessage"`
+}
+
+func newError(line int, tagName, message string) Error {
+	return Error{
+		Line:             line,
+		Message:          message,
+		TagName:          tagName,
+		FormattedMessage: fmt.Sprintf("Line %d (%s) — %s", line, tagName, message),
+	}
+}
+
+type renderResult struct {
+	html  string
+	stats *mjmlgo.RenderStats
+	err   error
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/render" {
+		writeJSON(w, http.StatusNotFound, errorResponse{Message: "not found"})
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Message: "method not allowed"})
+		return
+	}
+
+	maxBodySize := h.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+
+	var req renderRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{Message: fmt.Sprintf("request body exceeds %d bytes", maxBodySize)})
+			return
+		}
+
+		writeJSON(w, http.StatusBadRequest, errorResponse{Message: "invalid request body: " + err.Error()})
+		return
+	}
+
+	if strings.TrimSpace(req.MJML) == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Message: "mjml is required"})
+		return
+	}
+
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	sem := h.semaphore()
+	select {
+	case sem <- struct{}{}:
+	default:
+		w.Header().Set("Retry-After", "1")
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Message: "too many concurrent renders"})
+		return
+	}
+
+	// Rendering can not be interrupted, the goroutine finishes in the background after a timeout
+	// and keeps its slot of the semaphore until then.
+	done := make(chan renderResult, 1)
+	go func() {
+		defer func() { <-sem }()
+
+		opts := append([]mjmlgo.Option{mjmlgo.WithValidationLevel(component.ValidationSoft)}, h.Options...)
+		html, stats, err := mjmlgo.RenderMJMLWithStats(strings.NewReader(req.MJML), opts...)
+		done <- renderResult{html: html, stats: stats, err: err}
+	}()
+
+	var result renderResult
+	select {
+	case <-ctx.Done():
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Message: "rendering timed out"})
+		return
+	case result = <-done:
+	}
+
+	if result.err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			Message: result.err.Error(),
+			Errors:  []Error{toError(result.err)},
+		})
+		return
+	}
+
+	errs := make([]Error, 0, len(result.stats.Recovered)+len(result.stats.ValidationErrors))
+	for _, syntaxErr := range result.stats.Recovered {
+		errs = append(errs, toError(syntaxErr))
+	}
+	for _, validationErr := range result.stats.ValidationErrors {
+		errs = append(errs, toError(validationErr))
+	}
+
+	writeJSON(w, http.StatusOK, renderResponse{
+		HTML:        result.html,
+		MJML:        req.MJML,
+		MJMLVersion: MJMLVersion,
+		Errors:      errs,
+	})
+}
+
+func toError(err error) Error {
+	var (
+		validationErr *component.ValidationError
+		syntaxErr     *mjmlgo.SyntaxError
+	)
+
+	switch {
+	case errors.As(err, &validationErr):
+		return newError(validationErr.Line, validationErr.Tag, validationErr.Error())
+	case errors.As(err, &syntaxErr):
+		return newError(syntaxErr.Line, "", err.Error())
+	default:
+		return newError(0, "", err.Error())
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
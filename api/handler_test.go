@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/mjmlgo"
+	"github.com/julez-dev/mjmlgo/component"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	do := func(h http.Handler, method, path, body string) (*httptest.ResponseRecorder, map[string]any) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return rec, resp
+	}
+
+	mjmlJSON := func(mjml string) string {
+		b, err := json.Marshal(map[string]string{"mjml": mjml})
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	t.Run("render", func(t *testing.T) {
+		input := `<mjml><mj-body><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section></mj-body></mjml>`
+		rec, resp := do(&Handler{}, http.MethodPost, "/v1/render", mjmlJSON(input))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		require.Contains(t, resp["html"], "Hello")
+		require.Equal(t, input, resp["mjml"])
+		require.Equal(t, MJMLVersion, resp["mjml_version"])
+		require.Equal(t, []any{}, resp["errors"])
+	})
+
+	t.Run("validation-errors", func(t *testing.T) {
+		input := "<mjml>\n<mj-body>\n<mj-section>\n<mj-column>\n<mj-text color=\"nope\">Hello</mj-text>\n</mj-column>\n</mj-section>\n</mj-body>\n</mjml>"
+		rec, resp := do(&Handler{}, http.MethodPost, "/v1/render", mjmlJSON(input))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Contains(t, resp["html"], "Hello")
+
+		errs := resp["errors"].([]any)
+		require.Len(t, errs, 1)
+
+		first := errs[0].(map[string]any)
+		require.Equal(t, float64(5), first["line"])
+		require.Equal(t, "mj-text", first["tagName"])
+		require.Contains(t, first["message"], "failed to validate field color")
+		require.Contains(t, first["formattedMessage"], "Line 5 (mj-text)")
+	})
+
+	t.Run("recovery", func(t *testing.T) {
+		h := &Handler{Options: []mjmlgo.Option{mjmlgo.WithRecovery()}}
+		input := "<mjml>\n<mj-body>\n<mj-section>\n<mj-column>\n<mj-text>Hello"
+		rec, resp := do(h, http.MethodPost, "/v1/render", mjmlJSON(input))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Contains(t, resp["html"], "Hello")
+
+		errs := resp["errors"].([]any)
+		require.Len(t, errs, 5)
+		require.Equal(t, float64(5), errs[0].(map[string]any)["line"])
+	})
+
+	t.Run("strict-validation", func(t *testing.T) {
+		h := &Handler{Options: []mjmlgo.Option{mjmlgo.WithValidationLevel(component.ValidationStrict)}}
+		input := `<mjml><mj-body><mj-section><mj-column><mj-text color="nope">Hello</mj-text></mj-column></mj-section></mj-body></mjml>`
+		rec, resp := do(h, http.MethodPost, "/v1/render", mjmlJSON(input))
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, resp["message"], "failed to validate field color")
+		require.Len(t, resp["errors"], 1)
+	})
+
+	t.Run("bad-requests", func(t *testing.T) {
+		rec, _ := do(&Handler{}, http.MethodPost, "/v1/render", "{")
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec, _ = do(&Handler{}, http.MethodPost, "/v1/render", `{"mjml": ""}`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec, _ = do(&Handler{}, http.MethodGet, "/v1/render", "")
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+		rec, _ = do(&Handler{}, http.MethodPost, "/v2/render", "")
+		require.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec, _ = do(&Handler{MaxBodySize: 10}, http.MethodPost, "/v1/render", mjmlJSON("<mjml></mjml>"))
+		require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+		rec, resp := do(&Handler{}, http.MethodPost, "/v1/render", mjmlJSON("<mjml><mj-body>"))
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.NotEmpty(t, resp["message"])
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		input := `<mjml><mj-body><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section></mj-body></mjml>`
+		rec, resp := do(&Handler{Timeout: time.Nanosecond}, http.MethodPost, "/v1/render", mjmlJSON(input))
+
+		// rendering might finish before the timer fires
+		if rec.Code != http.StatusOK {
+			require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+			require.Equal(t, "rendering timed out", resp["message"])
+		}
+	})
+
+	t.Run("concurrency-limit", func(t *testing.T) {
+		input := `<mjml><mj-body><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section></mj-body></mjml>`
+		h := &Handler{MaxConcurrent: 1}
+
+		// occupy the only slot like a render still running in the background
+		h.semaphore() <- struct{}{}
+		rec, resp := do(h, http.MethodPost, "/v1/render", mjmlJSON(input))
+		require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		require.Equal(t, "too many concurrent renders", resp["message"])
+
+		<-h.semaphore()
+		rec, _ = do(h, http.MethodPost, "/v1/render", mjmlJSON(input))
+		require.Equal(t, http.StatusOK, rec.Code)
+	})
+}
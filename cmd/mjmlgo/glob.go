@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// expandInputs resolves the glob patterns in inputs to file names.
+// Besides the patterns supported by filepath.Glob, a "**" path segment matches any number of directories.
+func expandInputs(inputs []string) ([]string, error) {
+	var files []string
+
+	for _, input := range inputs {
+		if !strings.ContainsAny(input, "*?[") {
+			files = append(files, input)
+			continue
+		}
+
+		var (
+			matches []string
+			err     error
+		)
+
+		if strings.Contains(input, "**") {
+			matches, err = globRecursive(input)
+		} else {
+			matches, err = filepath.Glob(input)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", input, err)
+		}
+
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", input)
+		}
+
+		files = append(files, matches...)
+	}
+
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+func globRecursive(pattern string) ([]string, error) {
+	root, rest, _ := strings.Cut(filepath.ToSlash(pattern), "**")
+	root = strings.TrimSuffix(root, "/")
+	if root == "" {
+		root = "."
+	}
+
+	rest = strings.TrimPrefix(rest, "/")
+	restSegments := strings.Split(rest, "/")
+
+	var matches []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		segments := strings.Split(filepath.ToSlash(path), "/")
+		if len(segments) < len(restSegments) {
+			return nil
+		}
+
+		matched := rest == ""
+		if !matched {
+			matched, err = filepath.Match(rest, strings.Join(segments[len(segments)-len(restSegments):], "/"))
+			if err != nil {
+				return err
+			}
+		}
+
+		if matched {
+			matches = append(matches, path)
+		}
+
+		return nil
+	})
+
+	return matches, err
+}
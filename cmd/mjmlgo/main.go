@@ -0,0 +1,315 @@
+// Command mjmlgo renders MJML templates to HTML.
+// Its flags mirror the ones of the mjml command line tool.
+//
+//	mjmlgo [flags] <files or globs...>
+//	mjmlgo -i -s < input.mjml > output.html
+//	mjmlgo serve [-addr localhost:8080] [directory]
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/julez-dev/mjmlgo"
+	"github.com/julez-dev/mjmlgo/component"
+	"golang.org/x/net/html"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+const stdinName = "<stdin>"
+
+type config struct {
+	output          string
+	stdout          bool
+	stdin           bool
+	validate        bool
+	watch           bool
+	minify          bool
+	keepComments    bool
+	validationLevel component.ValidationLevel
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "serve" {
+		return runServe(args[1:], stderr)
+	}
+
+	cfg, inputs, err := parseFlags(args, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		fmt.Fprintln(stderr, "mjmlgo:", err)
+		return exitUsage
+	}
+
+	if cfg.watch && (cfg.stdin || len(inputs) == 0) {
+		fmt.Fprintln(stderr, "mjmlgo: watch mode requires input files")
+		return exitUsage
+	}
+
+	if cfg.stdin || len(inputs) == 0 {
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintln(stderr, "mjmlgo:", err)
+			return exitError
+		}
+
+		if !processFile(cfg, stdinName, b, false, stdout, stderr) {
+			return exitError
+		}
+		return exitOK
+	}
+
+	if cfg.watch {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if err := newWatcher(cfg, inputs, stdout, stderr).run(ctx); err != nil {
+			fmt.Fprintln(stderr, "mjmlgo:", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	files, err := expandInputs(inputs)
+	if err != nil {
+		fmt.Fprintln(stderr, "mjmlgo:", err)
+		return exitUsage
+	}
+
+	code := exitOK
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintln(stderr, "mjmlgo:", err)
+			code = exitError
+			continue
+		}
+
+		if !processFile(cfg, file, b, len(files) > 1, stdout, stderr) {
+			code = exitError
+		}
+	}
+
+	return code
+}
+
+func parseFlags(args []string, stderr io.Writer) (config, []string, error) {
+	var (
+		cfg             config
+		validationLevel string
+	)
+
+	fs := flag.NewFlagSet("mjmlgo", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: mjmlgo [flags] <files or globs...>")
+		fs.PrintDefaults()
+	}
+
+	fs.Bool("r", true, "render the input files, this is the default")
+	fs.StringVar(&cfg.output, "o", "", "output file or directory")
+	fs.StringVar(&cfg.output, "output", "", "output file or directory")
+	fs.BoolVar(&cfg.stdout, "s", false, "write the rendered HTML to stdout")
+	fs.BoolVar(&cfg.stdout, "stdout", false, "write the rendered HTML to stdout")
+	fs.BoolVar(&cfg.stdin, "i", false, "read MJML from stdin")
+	fs.BoolVar(&cfg.stdin, "stdin", false, "read MJML from stdin")
+	fs.BoolVar(&cfg.validate, "v", false, "only validate the input files")
+	fs.BoolVar(&cfg.validate, "validate", false, "only validate the input files")
+	fs.BoolVar(&cfg.watch, "w", false, "watch the input files and render them on change")
+	fs.BoolVar(&cfg.watch, "watch", false, "watch the input files and render them on change")
+	fs.BoolVar(&cfg.minify, "config.minify", false, "minify the rendered HTML")
+	fs.BoolVar(&cfg.keepComments, "config.keepComments", true, "keep comments in the rendered HTML")
+	fs.StringVar(&validationLevel, "config.validationLevel", string(component.ValidationSoft), "validation level: strict, soft or skip")
+
+	// Unlike the flag package, the mjml CLI accepts flags after the input files.
+	var inputs []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return config{}, nil, err
+		}
+
+		if fs.NArg() == 0 {
+			break
+		}
+
+		inputs = append(inputs, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	switch level := component.ValidationLevel(validationLevel); level {
+	case component.ValidationStrict, component.ValidationSoft, component.ValidationSkip:
+		cfg.validationLevel = level
+	default:
+		return config{}, nil, fmt.Errorf("invalid validation level %q", validationLevel)
+	}
+
+	return cfg, inputs, nil
+}
+
+// processFile validates or renders a single input and reports whether it succeeded.
+func processFile(cfg config, name string, input []byte, multiple bool, stdout, stderr io.Writer) bool {
+	if cfg.validate {
+		validationErrs, err := mjmlgo.Validate(bytes.NewReader(input))
+		if err != nil {
+			printDiagnostic(stderr, name, err)
+			return false
+		}
+
+		for _, validationErr := range validationErrs {
+			printDiagnostic(stderr, name, validationErr)
+		}
+
+		return len(validationErrs) == 0
+	}
+
+	html, err := renderFile(cfg, name, input, stderr)
+	if err != nil {
+		printDiagnostic(stderr, name, err)
+		return false
+	}
+
+	if err := writeOutput(cfg, name, html, multiple, stdout); err != nil {
+		fmt.Fprintln(stderr, "mjmlgo:", err)
+		return false
+	}
+
+	return true
+}
+
+// renderFile renders input and prints the validation errors collected in soft mode as warnings.
+func renderFile(cfg config, name string, input []byte, stderr io.Writer) (string, error) {
+	html, stats, err := mjmlgo.RenderMJMLWithStats(
+		bytes.NewReader(input),
+		mjmlgo.WithValidationLevel(cfg.validationLevel),
+		mjmlgo.WithKeepComments(cfg.keepComments),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	for _, validationErr := range stats.ValidationErrors {
+		printDiagnostic(stderr, name, validationErr)
+	}
+
+	if cfg.minify {
+		html = minifyHTML(html)
+	}
+
+	return html, nil
+}
+
+func writeOutput(cfg config, name, html string, multiple bool, stdout io.Writer) error {
+	if cfg.stdout || (name == stdinName && cfg.output == "") {
+		_, err := io.WriteString(stdout, html)
+		return err
+	}
+
+	target := outputPath(cfg.output, name, multiple)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(target, []byte(html), 0o644)
+}
+
+// outputPath returns the path the rendered HTML of the input file name is written to.
+// output is treated as a directory if it exists as one, ends with a path separator or multiple files are rendered.
+func outputPath(output, name string, multiple bool) string {
+	base := "index.html"
+	if name != stdinName {
+		base = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name)) + ".html"
+	}
+
+	if output == "" {
+		return base
+	}
+
+	if info, err := os.Stat(output); (err == nil && info.IsDir()) || strings.HasSuffix(output, string(filepath.Separator)) || multiple {
+		return filepath.Join(output, base)
+	}
+
+	return output
+}
+
+func printDiagnostic(w io.Writer, name string, err error) {
+	var (
+		validationErr *component.ValidationError
+		syntaxErr     *mjmlgo.SyntaxError
+	)
+
+	switch {
+	case errors.As(err, &validationErr):
+		fmt.Fprintf(w, "%s:%d:%d: %s\n", name, validationErr.Line, validationErr.Column, err)
+	case errors.As(err, &syntaxErr):
+		fmt.Fprintf(w, "%s:%d:%d: %s\n", name, syntaxErr.Line, syntaxErr.Column, err)
+	default:
+		fmt.Fprintf(w, "%s: %s\n", name, err)
+	}
+}
+
+var whitespaceRun = regexp.MustCompile(`[ \t\r\n\f]+`)
+
+// preformattedElements keep the whitespace of their content when minifying.
+var preformattedElements = map[string]struct{}{
+	"pre":      {},
+	"textarea": {},
+	"script":   {},
+}
+
+// minifyHTML collapses runs of whitespace in text, styles and comments like the conditional comments for Outlook
+// to a single space. Whitespace between inline elements is visible, so it is never removed completely,
+// and the content of pre, textarea and script is kept as is.
+func minifyHTML(s string) string {
+	var (
+		b            strings.Builder
+		z            = html.NewTokenizer(strings.NewReader(s))
+		preformatted int
+	)
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		raw := string(z.Raw())
+		switch tt {
+		case html.StartTagToken, html.EndTagToken:
+			name, _ := z.TagName()
+			if _, ok := preformattedElements[string(name)]; ok {
+				if tt == html.StartTagToken {
+					preformatted++
+				} else if preformatted > 0 {
+					preformatted--
+				}
+			}
+		case html.TextToken, html.CommentToken:
+			if preformatted == 0 {
+				raw = whitespaceRun.ReplaceAllString(raw, " ")
+			}
+		}
+		b.WriteString(raw)
+	}
+
+	return strings.TrimSpace(b.String())
+}
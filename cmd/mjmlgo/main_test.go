@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const validInput = `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text>Hello World</mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+const invalidInput = `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text color="not-a-color">Hello World</mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stdin-stdout", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-i", "-s"}, strings.NewReader(validInput), &stdout, &stderr)
+		require.Equal(t, exitOK, code, stderr.String())
+		require.Contains(t, stdout.String(), "Hello World")
+		require.True(t, strings.HasPrefix(stdout.String(), "<!DOCTYPE html>"))
+	})
+
+	t.Run("minify", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"--config.minify"}, strings.NewReader(validInput), &stdout, &stderr)
+		require.Equal(t, exitOK, code, stderr.String())
+		require.NotContains(t, stdout.String(), ">\n<")
+
+		stdout.Reset()
+		input := strings.Replace(validInput, "<mj-text>", "<mj-text><b>Hello</b>\n  <i>World</i><pre>a\n  b</pre>", 1)
+		code = run([]string{"--config.minify"}, strings.NewReader(input), &stdout, &stderr)
+		require.Equal(t, exitOK, code, stderr.String())
+		require.Contains(t, stdout.String(), "<b>Hello</b> <i>World</i>")
+		require.Contains(t, stdout.String(), "<pre>a\n  b</pre>")
+	})
+
+	t.Run("keep-comments", func(t *testing.T) {
+		input := strings.Replace(validInput, "<mj-text>", "<!-- greeting --><mj-text>", 1)
+
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-s"}, strings.NewReader(input), &stdout, &stderr)
+		require.Equal(t, exitOK, code, stderr.String())
+		require.Contains(t, stdout.String(), "<!-- greeting -->")
+
+		stdout.Reset()
+		code = run([]string{"-s", "--config.keepComments=false"}, strings.NewReader(input), &stdout, &stderr)
+		require.Equal(t, exitOK, code, stderr.String())
+		require.NotContains(t, stdout.String(), "greeting")
+	})
+
+	t.Run("files-to-dir", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "src", "nested"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "a.mjml"), []byte(validInput), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "nested", "b.mjml"), []byte(validInput), 0o644))
+
+		out := filepath.Join(dir, "out")
+
+		var stdout, stderr bytes.Buffer
+		code := run([]string{filepath.Join(dir, "src", "**", "*.mjml"), "-o", out}, nil, &stdout, &stderr)
+		require.Equal(t, exitOK, code, stderr.String())
+
+		for _, name := range []string{"a.html", "b.html"} {
+			b, err := os.ReadFile(filepath.Join(out, name))
+			require.NoError(t, err)
+			require.Contains(t, string(b), "Hello World")
+		}
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"--validate"}, strings.NewReader(invalidInput), &stdout, &stderr)
+		require.Equal(t, exitError, code)
+		require.Empty(t, stdout.String())
+		require.Contains(t, stderr.String(), "<stdin>:5:9: failed to validate field color in <mj-text>")
+	})
+
+	t.Run("validation-levels", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-s"}, strings.NewReader(invalidInput), &stdout, &stderr)
+		require.Equal(t, exitOK, code)
+		require.Contains(t, stdout.String(), "Hello World")
+		require.Contains(t, stderr.String(), "<stdin>:5:9:")
+
+		stdout.Reset()
+		stderr.Reset()
+		code = run([]string{"-s", "--config.validationLevel=strict"}, strings.NewReader(invalidInput), &stdout, &stderr)
+		require.Equal(t, exitError, code)
+		require.Empty(t, stdout.String())
+
+		stdout.Reset()
+		stderr.Reset()
+		code = run([]string{"-s", "--config.validationLevel=skip"}, strings.NewReader(invalidInput), &stdout, &stderr)
+		require.Equal(t, exitOK, code)
+		require.Empty(t, stderr.String())
+
+		code = run([]string{"--config.validationLevel=unknown"}, strings.NewReader(invalidInput), &stdout, &stderr)
+		require.Equal(t, exitUsage, code)
+	})
+
+	t.Run("parse-error", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := run(nil, strings.NewReader("<mjml>\n<mj-body>\n<mj-section"), &stdout, &stderr)
+		require.Equal(t, exitError, code)
+		require.Contains(t, stderr.String(), "<stdin>:3:")
+	})
+}
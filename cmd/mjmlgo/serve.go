@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julez-dev/mjmlgo"
+	"github.com/julez-dev/mjmlgo/component"
+)
+
+const reloadScript = `<script>new EventSource("/events").onmessage = function () { location.reload(); };</script>`
+
+var serveTemplates = template.Must(template.New("index").Parse(`<!doctype html>
+<html><head><title>mjmlgo</title>` + reloadScript + `</head>
+<body style="font-family:sans-serif">
+<h1>{{ .Dir }}</h1>
+<ul>
+{{ range .Files }}<li><a href="/preview/{{ . }}">{{ . }}</a> (<a href="/render/{{ . }}">html</a>)</li>
+{{ else }}<li>no .mjml files found</li>
+{{ end }}
+</ul>
+</body></html>
+`))
+
+func init() {
+	template.Must(serveTemplates.New("preview").Parse(`<!doctype html>
+<html><head><title>{{ .File }}</title>` + reloadScript + `</head>
+<body style="margin:0;font-family:sans-serif;background:#eeeeee">
+<div style="display:flex;gap:16px;padding:16px;align-items:flex-start">
+  <div style="flex:1">
+    <p>Desktop</p>
+    <iframe src="/render/{{ .File }}?embed=1" style="width:100%;height:90vh;border:0;background:#ffffff"></iframe>
+  </div>
+  <div>
+    <p>Mobile ({{ .MobileWidth }}px, breakpoint {{ .Breakpoint }})</p>
+    <iframe src="/render/{{ .File }}?embed=1" style="width:{{ .MobileWidth }}px;height:90vh;border:0;background:#ffffff"></iframe>
+  </div>
+</div>
+</body></html>
+`))
+
+	template.Must(serveTemplates.New("error").Parse(`<!doctype html>
+<html><head><title>Error in {{ .File }}</title>{{ if not .Embed }}` + reloadScript + `{{ end }}</head>
+<body style="font-family:sans-serif;background:#fff0f0;padding:16px">
+<h1>{{ .File }} could not be rendered</h1>
+<pre style="white-space:pre-wrap">{{ .Error }}</pre>
+</body></html>
+`))
+
+	template.Must(serveTemplates.New("overlay").Parse(`<div id="mjmlgo-overlay" style="position:fixed;left:0;right:0;bottom:0;max-height:50%;overflow:auto;z-index:9999;margin:0;padding:12px;background:rgba(60,0,0,0.9);color:#ffffff;font:13px monospace;text-align:left">
+<strong>{{ len . }} validation error(s)</strong>
+<button onclick="this.parentNode.remove()" style="float:right">close</button>
+<ul>
+{{ range . }}<li>{{ .Line }}:{{ .Column }}: {{ .Error }}</li>
+{{ end }}</ul>
+</div>
+`))
+}
+
+// server serves the MJML templates of a directory as rendered HTML and notifies
+// connected browsers about changes using Server-Sent Events.
+type server struct {
+	root     string
+	interval time.Duration
+	stderr   io.Writer
+
+	mu      sync.Mutex
+	clients map[chan string]struct{}
+}
+
+func newServer(root string, stderr io.Writer) *server {
+	return &server{
+		root:     root,
+		interval: defaultWatchInterval,
+		stderr:   stderr,
+		clients:  make(map[chan string]struct{}),
+	}
+}
+
+func runServe(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("mjmlgo serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: mjmlgo serve [flags] [directory]")
+		fs.PrintDefaults()
+	}
+
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	root := "."
+	if fs.NArg() > 0 {
+		root = fs.Arg(0)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	s := newServer(root, stderr)
+	go s.watch(ctx)
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(stderr, "mjmlgo: serving %s on http://%s\n", root, *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(stderr, "mjmlgo:", err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /events", s.handleEvents)
+	mux.HandleFunc("GET /preview/{file...}", s.handlePreview)
+	mux.HandleFunc("GET /render/{file...}", s.handleRender)
+	return mux
+}
+
+// templateFiles returns the slash separated paths of all .mjml files below the root directory.
+func (s *server) templateFiles() ([]string, error) {
+	var files []string
+	err := fs.WalkDir(os.DirFS(s.root), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && path.Ext(p) == ".mjml" {
+			files = append(files, p)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// readTemplate reads the template file relative to the root directory.
+func (s *server) readTemplate(file string) ([]byte, error) {
+	if !fs.ValidPath(file) || path.Ext(file) != ".mjml" {
+		return nil, fs.ErrNotExist
+	}
+
+	return os.ReadFile(filepath.Join(s.root, filepath.FromSlash(file)))
+}
+
+type renderResult struct {
+	html       string
+	breakpoint string
+}
+
+// render renders the template and adds the live reload script and the validation error overlay.
+func (s *server) render(input []byte, embed bool) (renderResult, error) {
+	var result renderResult
+
+	html, _, err := mjmlgo.RenderMJMLWithStats(bytes.NewReader(input),
+		mjmlgo.WithValidationLevel(component.ValidationSoft),
+		mjmlgo.WithHeadHook(func(ctx *component.RenderContext, w io.Writer) error {
+			result.breakpoint = ctx.Breakpoint
+			if embed {
+				return nil
+			}
+			_, err := io.WriteString(w, reloadScript)
+			return err
+		}),
+		mjmlgo.WithAfterBodyHook(func(ctx *component.RenderContext, w io.Writer) error {
+			if len(ctx.ValidationErrors) == 0 {
+				return nil
+			}
+			return serveTemplates.ExecuteTemplate(w, "overlay", ctx.ValidationErrors)
+		}),
+	)
+	if err != nil {
+		return renderResult{}, err
+	}
+
+	result.html = html
+	return result, nil
+}
+
+func (s *server) handleIndex(w http.ResponseWriter, _ *http.Request) {
+	files, err := s.templateFiles()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = serveTemplates.ExecuteTemplate(w, "index", map[string]any{
+		"Dir":   s.root,
+		"Files": files,
+	})
+}
+
+func (s *server) handleRender(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	embed := r.URL.Query().Has("embed")
+
+	input, err := s.readTemplate(file)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	result, err := s.render(input, embed)
+	if err != nil {
+		var b strings.Builder
+		printDiagnostic(&b, file, err)
+
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = serveTemplates.ExecuteTemplate(w, "error", map[string]any{
+			"File":  file,
+			"Error": b.String(),
+			"Embed": embed,
+		})
+		return
+	}
+
+	_, _ = io.WriteString(w, result.html)
+}
+
+func (s *server) handlePreview(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+
+	input, err := s.readTemplate(file)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	breakpoint := "480px"
+	if result, err := s.render(input, true); err == nil && result.breakpoint != "" {
+		breakpoint = result.breakpoint
+	}
+
+	// The mobile preview uses the widest viewport that still gets the mobile layout.
+	mobileWidth := 479
+	if v, err := strconv.Atoi(component.RemoveNonNumeric(breakpoint)); err == nil && v > 1 {
+		mobileWidth = v - 1
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = serveTemplates.ExecuteTemplate(w, "preview", map[string]any{
+		"File":        file,
+		"Breakpoint":  breakpoint,
+		"MobileWidth": mobileWidth,
+	})
+}
+
+func (s *server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Subscribe before sending the headers, so no change is missed once the client is connected.
+	events := s.subscribe()
+	defer s.unsubscribe(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case file := <-events:
+			fmt.Fprintf(w, "data: %s\n\n", file)
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *server) subscribe() chan string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make(chan string, 16)
+	s.clients[events] = struct{}{}
+	return events
+}
+
+func (s *server) unsubscribe(events chan string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.clients, events)
+}
+
+// broadcast notifies all connected clients about changed files, one event per file.
+// Events for clients which have not consumed the previous ones are dropped, since they reload anyway.
+func (s *server) broadcast(files ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for events := range s.clients {
+		for _, file := range files {
+			select {
+			case events <- file:
+			default:
+			}
+		}
+	}
+}
+
+// watch polls the templates and broadcasts changes until ctx is done.
+func (s *server) watch(ctx context.Context) {
+	tracked := make(modTimes)
+	s.scan(tracked)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if changed := s.scan(tracked); len(changed) > 0 {
+				fmt.Fprintf(s.stderr, "mjmlgo: changed %s\n", strings.Join(changed, ", "))
+				s.broadcast(changed...)
+			}
+		}
+	}
+}
+
+// scan updates the tracked modification times and returns the files changed since the last scan.
+func (s *server) scan(tracked modTimes) []string {
+	files, err := s.templateFiles()
+	if err != nil {
+		fmt.Fprintln(s.stderr, "mjmlgo:", err)
+		return nil
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, filepath.Join(s.root, filepath.FromSlash(file)))
+	}
+
+	// files which disappeared since the last scan
+	for file := range tracked {
+		if !slices.Contains(paths, file) {
+			paths = append(paths, file)
+		}
+	}
+
+	var changed []string
+	for _, path := range slices.Sorted(slices.Values(paths)) {
+		if tracked.update(path) {
+			changed = append(changed, path)
+		}
+	}
+
+	return changed
+}
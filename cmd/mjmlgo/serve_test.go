@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "valid.mjml"), []byte(validInput), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nested", "invalid.mjml"), []byte(invalidInput), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.mjml"), []byte("<mjml><mj-body>"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "breakpoint.mjml"), []byte(`<mjml>
+  <mj-head><mj-breakpoint width="600px" /></mj-head>
+  <mj-body><mj-section><mj-column><mj-text>Hi</mj-text></mj-column></mj-section></mj-body>
+</mjml>`), 0o644))
+
+	s := newServer(dir, io.Discard)
+	ts := httptest.NewServer(s.handler())
+	defer ts.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(ts.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(b)
+	}
+
+	t.Run("index", func(t *testing.T) {
+		status, body := get("/")
+		require.Equal(t, http.StatusOK, status)
+		require.Contains(t, body, `href="/preview/valid.mjml"`)
+		require.Contains(t, body, `href="/preview/nested/invalid.mjml"`)
+	})
+
+	t.Run("render", func(t *testing.T) {
+		status, body := get("/render/valid.mjml")
+		require.Equal(t, http.StatusOK, status)
+		require.Contains(t, body, "Hello World")
+		require.Contains(t, body, `new EventSource("/events")`)
+		require.NotContains(t, body, "mjmlgo-overlay")
+
+		_, body = get("/render/valid.mjml?embed=1")
+		require.NotContains(t, body, "EventSource")
+	})
+
+	t.Run("validation-overlay", func(t *testing.T) {
+		status, body := get("/render/nested/invalid.mjml")
+		require.Equal(t, http.StatusOK, status)
+		require.Contains(t, body, "Hello World")
+		require.Contains(t, body, "mjmlgo-overlay")
+		require.Contains(t, body, "5:9: failed to validate field color in &lt;mj-text&gt;")
+	})
+
+	t.Run("error-page", func(t *testing.T) {
+		status, body := get("/render/broken.mjml")
+		require.Equal(t, http.StatusUnprocessableEntity, status)
+		require.Contains(t, body, "broken.mjml could not be rendered")
+		require.Contains(t, body, "EventSource")
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		status, _ := get("/render/missing.mjml")
+		require.Equal(t, http.StatusNotFound, status)
+
+		status, _ = get("/render/../main.go")
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("preview", func(t *testing.T) {
+		_, body := get("/preview/valid.mjml")
+		require.Contains(t, body, "width:479px")
+
+		_, body = get("/preview/breakpoint.mjml")
+		require.Contains(t, body, "width:599px")
+		require.Contains(t, body, "breakpoint 600px")
+	})
+
+	t.Run("events", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events", nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		tracked := make(modTimes)
+		s.scan(tracked)
+		require.Empty(t, s.scan(tracked))
+
+		future := time.Now().Add(time.Hour)
+		valid := filepath.Join(dir, "valid.mjml")
+		breakpoint := filepath.Join(dir, "breakpoint.mjml")
+		require.NoError(t, os.Chtimes(valid, future, future))
+		require.NoError(t, os.Chtimes(breakpoint, future, future))
+
+		changed := s.scan(tracked)
+		require.Equal(t, []string{breakpoint, valid}, changed)
+		s.broadcast(changed...)
+
+		r := bufio.NewReader(resp.Body)
+		var events []string
+		for len(events) < 2 {
+			line, err := r.ReadString('\n')
+			require.NoError(t, err)
+			if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+				events = append(events, data)
+			}
+		}
+		require.Equal(t, changed, events)
+	})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const defaultWatchInterval = 500 * time.Millisecond
+
+// watcher polls the input files for changes
+// and re-renders the changed inputs. Errors after the first poll are logged and polling continues.
+type watcher struct {
+	cfg      config
+	inputs   []string
+	interval time.Duration
+	stdout   io.Writer
+	stderr   io.Writer
+	modTimes modTimes
+}
+
+func newWatcher(cfg config, inputs []string, stdout, stderr io.Writer) *watcher {
+	return &watcher{
+		cfg:      cfg,
+		inputs:   inputs,
+		interval: defaultWatchInterval,
+		stdout:   stdout,
+		stderr:   stderr,
+		modTimes: make(modTimes),
+	}
+}
+
+func (w *watcher) run(ctx context.Context) error {
+	if err := w.poll(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w.stderr, "mjmlgo: watching for changes")
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// e.g. while an editor replaces a file with a rename, a glob can briefly match nothing
+			if err := w.poll(); err != nil {
+				fmt.Fprintln(w.stderr, "mjmlgo:", err)
+			}
+		}
+	}
+}
+
+// poll renders all inputs which are new or changed since the last call.
+func (w *watcher) poll() error {
+	files, err := expandInputs(w.inputs)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if w.modTimes.update(file) {
+			w.render(file, len(files) > 1)
+		}
+	}
+
+	return nil
+}
+
+// modTimes tracks the modification times of files.
+type modTimes map[string]time.Time
+
+// update records the modification time of file and reports whether it changed since the last call.
+// Files which are created or removed count as changed.
+func (m modTimes) update(file string) bool {
+	info, err := os.Stat(file)
+	if err != nil {
+		_, known := m[file]
+		delete(m, file)
+		return known
+	}
+
+	last, known := m[file]
+	m[file] = info.ModTime()
+
+	return !known || !last.Equal(info.ModTime())
+}
+
+func (w *watcher) render(file string, multiple bool) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintln(w.stderr, "mjmlgo:", err)
+		return
+	}
+
+	if processFile(w.cfg, file, b, multiple, w.stdout, w.stderr) {
+		fmt.Fprintf(w.stderr, "mjmlgo: rendered %s\n", file)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/mjmlgo/component"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out") + string(filepath.Separator)
+
+	a := filepath.Join(dir, "a.mjml")
+	b := filepath.Join(dir, "b.mjml")
+
+	require.NoError(t, os.WriteFile(a, []byte(validInput), 0o644))
+	require.NoError(t, os.WriteFile(b, []byte(validInput), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	w := newWatcher(config{output: out, validationLevel: component.ValidationSoft}, []string{filepath.Join(dir, "*.mjml")}, &stdout, &stderr)
+
+	rendered := func() []string {
+		var files []string
+		for line := range strings.Lines(stderr.String()) {
+			if file, ok := strings.CutPrefix(strings.TrimSpace(line), "mjmlgo: rendered "); ok {
+				files = append(files, filepath.Base(file))
+			}
+		}
+		stderr.Reset()
+		return files
+	}
+
+	touch := func(file string) {
+		future := time.Now().Add(time.Hour)
+		require.NoError(t, os.Chtimes(file, future, future))
+	}
+
+	require.NoError(t, w.poll())
+	require.Equal(t, []string{"a.mjml", "b.mjml"}, rendered())
+	require.FileExists(t, filepath.Join(out, "a.html"))
+
+	require.NoError(t, w.poll())
+	require.Empty(t, rendered())
+
+	touch(a)
+	require.NoError(t, w.poll())
+	require.Equal(t, []string{"a.mjml"}, rendered())
+
+	require.NoError(t, os.WriteFile(b, []byte(invalidInput), 0o644))
+	touch(b)
+	require.NoError(t, w.poll())
+	output := stderr.String()
+	require.Equal(t, []string{"b.mjml"}, rendered())
+	require.Contains(t, output, "b.mjml:5:9: failed to validate field color")
+
+	c := filepath.Join(dir, "c.mjml")
+	require.NoError(t, os.WriteFile(c, []byte(validInput), 0o644))
+	require.NoError(t, w.poll())
+	require.Equal(t, []string{"c.mjml"}, rendered())
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatcherKeepsPolling(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.mjml")
+	require.NoError(t, os.WriteFile(a, []byte(validInput), 0o644))
+
+	var stdout, stderr lockedBuffer
+	w := newWatcher(config{output: filepath.Join(dir, "out") + string(filepath.Separator)}, []string{filepath.Join(dir, "*.mjml")}, &stdout, &stderr)
+	w.interval = 5 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.run(ctx) }()
+
+	require.Eventually(t, func() bool { return strings.Contains(stderr.String(), "watching for changes") }, time.Second, time.Millisecond)
+
+	// the glob matches nothing until the file is written again
+	require.NoError(t, os.Remove(a))
+	require.Eventually(t, func() bool { return strings.Contains(stderr.String(), "no files match") }, time.Second, time.Millisecond)
+
+	require.NoError(t, os.WriteFile(a, []byte(validInput), 0o644))
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(a, future, future))
+	require.Eventually(t, func() bool { return strings.Count(stderr.String(), "rendered") == 2 }, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -1,8 +1,9 @@
 package component
 
 import (
-	"fmt"
 	"io"
+	"maps"
+	"slices"
 
 	"github.com/julez-dev/mjmlgo/node"
 )
@@ -21,13 +22,55 @@ func InitComponent(ctx *RenderContext, comp Component, n *node.Node) error {
 		}
 	}
 
-	for field, validator := range comp.AllowedAttributes() {
+	if ctx.ValidationLevel == ValidationSkip {
+		return nil
+	}
+
+	allowed := comp.AllowedAttributes()
+	for _, field := range slices.Sorted(maps.Keys(allowed)) {
 		val := n.GetAttributeValueDefault(field)
 
-		if err := validator(val); err != nil {
-			return fmt.Errorf("failed to validate field %s in <%s>: %w", field, comp.Name(), err)
+		if err := allowed[field](val); err != nil {
+			validationErr := &ValidationError{
+				Tag:       comp.Name(),
+				Attribute: field,
+				Line:      n.Line,
+				Column:    n.Column,
+				Err:       err,
+			}
+
+			if ctx.ValidationLevel == ValidationSoft {
+				ctx.ValidationErrors = append(ctx.ValidationErrors, validationErr)
+				continue
+			}
+
+			return validationErr
 		}
 	}
 
 	return nil
 }
+
+// components maps tag names to the components rendering them.
+var components = map[string]Component{
+	BodyTagName:          MJMLBody{},
+	SectionTagName:       MJMLSection{},
+	WrapperTagName:       MJMLSection{IsWrapper: true},
+	ColumnTagName:        MJMLColumn{},
+	GroupTagName:         MJMLGroup{},
+	HeroTagName:          MJMLHero{},
+	TextTagName:          MJMLText{},
+	ButtonTagName:        MJMLButton{},
+	ImageTagName:         MJMLImage{},
+	TableTagName:         MJMLTable{},
+	SocialTagName:        MJMLSocial{},
+	SocialElementTagName: MJMLSocialElement{},
+	DividerTagName:       MJMLDivider{},
+	SpacerTagName:        MJMLSpacer{},
+}
+
+// Lookup returns the component rendering elements with the given tag name.
+func Lookup(tag string) (Component, bool) {
+	comp, ok := components[tag]
+	return comp, ok
+}
@@ -3,7 +3,12 @@ package component
 import (
 	"encoding/xml"
 	"fmt"
+	"io"
+	"slices"
 	"strconv"
+	"strings"
+
+	"github.com/julez-dev/mjmlgo/node"
 )
 
 type RenderContext struct {
@@ -12,6 +17,9 @@ type RenderContext struct {
 	InlineStyles         []Stylesheet
 	Fonts                map[string]string
 
+	// ClassAttributes maps the name of each <mj-class> to its attributes, applied to components with mj-class="name".
+	ClassAttributes map[string][]xml.Attr
+
 	MJMLStylesheet              map[string][]string
 	IncludeMobileFullWidthStyle bool
 	ContainerWidth              string
@@ -20,6 +28,91 @@ type RenderContext struct {
 
 	Language  string
 	Direction string
+
+	ValidationLevel  ValidationLevel
+	ValidationErrors []*ValidationError
+
+	// LinkRewriter is called for every link generated by a component.
+	// Links whose scheme is listed in LinkRewriterSkipSchemes are left untouched.
+	LinkRewriter            LinkRewriter
+	LinkRewriterSkipSchemes []string
+
+	// HeadHooks are executed right before the closing </head> tag.
+	HeadHooks []RenderHook
+	// BeforeBodyHooks and AfterBodyHooks are executed inside the <div lang dir> wrapper
+	// of <mj-body>, before the first and after the last child component.
+	BeforeBodyHooks []RenderHook
+	AfterBodyHooks  []RenderHook
+
+	// SectionSizes is filled while rendering <mj-body> with the number of bytes
+	// each top-level child rendered to, before CSS inlining.
+	SectionSizes []SectionSize
+
+	// SourceMap adds a SourceMapAttribute with the source position to the root element of each component.
+	SourceMap bool
+
+	// FileStart is filled while rendering <mjml> with the content of all <mj-raw position="file-start"> elements.
+	// It must be written before the rendered document, in front of the doctype.
+	FileStart []string
+
+	linkPosition int
+}
+
+// SectionSize is the rendered size of a top-level child of <mj-body>.
+type SectionSize struct {
+	Type     string
+	CSSClass string
+	Bytes    int
+}
+
+// RenderHook writes additional HTML into the rendered document.
+type RenderHook func(ctx *RenderContext, w io.Writer) error
+
+func runHooks(ctx *RenderContext, w io.Writer, hooks []RenderHook) error {
+	for _, hook := range hooks {
+		if err := hook(ctx, w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LinkInfo describes the origin of a link passed to a LinkRewriter.
+type LinkInfo struct {
+	// Component is the tag name of the component generating the link, e.g. mj-button.
+	Component string
+	// CSSClass is the css-class attribute of the component.
+	CSSClass string
+	// Position is the zero based index of the link in the rendered document.
+	Position int
+}
+
+// LinkRewriter returns the href that should be written instead of href.
+type LinkRewriter func(href string, info LinkInfo) string
+
+func (c *RenderContext) rewriteLink(component string, n *node.Node, href string) string {
+	if c.LinkRewriter == nil || href == "" {
+		return href
+	}
+
+	// skipped links do not count towards the position
+	if scheme, _, found := strings.Cut(href, ":"); found {
+		if slices.ContainsFunc(c.LinkRewriterSkipSchemes, func(s string) bool {
+			return strings.EqualFold(strings.TrimSuffix(s, ":"), strings.TrimSpace(scheme))
+		}) {
+			return href
+		}
+	}
+
+	position := c.linkPosition
+	c.linkPosition++
+
+	return c.LinkRewriter(href, LinkInfo{
+		Component: component,
+		CSSClass:  n.GetAttributeValueDefault("css-class"),
+		Position:  position,
+	})
 }
 
 func (c RenderContext) makeLowerBreakpoint() string {
@@ -0,0 +1,73 @@
+package component
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/julez-dev/mjmlgo/node"
+)
+
+// fragmentParents are the components which can be rendered as fragment, mapped to the parent they are rendered in.
+var fragmentParents = map[string]string{
+	SectionTagName: BodyTagName,
+	WrapperTagName: BodyTagName,
+	HeroTagName:    BodyTagName,
+	ColumnTagName:  SectionTagName,
+	GroupTagName:   SectionTagName,
+}
+
+// RenderFragment renders n, a component like mj-section or mj-column, without the surrounding document.
+// ctx.ContainerWidth is the width available to n, it defaults to the mj-body width of 600px.
+// head is an optional <mj-head> whose attributes, styles, fonts and breakpoint are applied like in a document.
+// The markup n requires in <head>, like the media queries of columns and the mj-style rules, is written to headW,
+// followed by the output of ctx.HeadHooks. The body hooks are not run, a fragment has no body.
+func RenderFragment(ctx *RenderContext, w, headW io.Writer, head, n *node.Node) error {
+	parentType, ok := fragmentParents[n.Type]
+	if !ok {
+		return fmt.Errorf("%w: <%s> can not be rendered as fragment", ErrMJMLBadlyFormatted, n.Type)
+	}
+
+	if head == nil {
+		head = &node.Node{Type: HeadTagName}
+	}
+
+	if ctx.ContainerWidth == "" {
+		ctx.ContainerWidth = MJMLBody{}.DefaultAttributes(ctx)["width"]
+	}
+	ctx.Breakpoint = "480px"
+	ctx.IncludeMobileFullWidthStyle = hasNodeType(n, ImageTagName)
+
+	var m MJML
+	if err := m.preparseHeadMetaValues(ctx, head); err != nil {
+		return err
+	}
+
+	// components look at their parent, e.g. a column at the number of columns in its section
+	if n.Parent == nil {
+		parent := &node.Node{Type: parentType}
+		parent.AppendChild(n)
+	}
+
+	m.setAttributeDefaults(ctx, n)
+
+	comp, _ := Lookup(n.Type)
+	if err := InitComponent(ctx, comp, n); err != nil {
+		return err
+	}
+
+	if err := comp.Render(ctx, w, n); err != nil {
+		return err
+	}
+
+	// like in a document the head is rendered last, it contains the styles registered by the components
+	var h MJMLHead
+	if err := h.renderStyles(ctx, headW, head); err != nil {
+		return fmt.Errorf("error rendering <mj-head>: %w", err)
+	}
+
+	if err := runHooks(ctx, headW, ctx.HeadHooks); err != nil {
+		return fmt.Errorf("error running head hooks: %w", err)
+	}
+
+	return nil
+}
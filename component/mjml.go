@@ -1,6 +1,7 @@
 package component
 
 import (
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
@@ -48,6 +49,13 @@ func (m MJML) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
 
 	ctx.Language = n.GetAttributeValueDefault("lang")
 
+	n.Walk(func(child *node.Node) bool {
+		if isFileStartRaw(child) {
+			ctx.FileStart = append(ctx.FileStart, strings.TrimSpace(child.Content))
+		}
+		return true
+	})
+
 	if err := templates.ExecuteTemplate(w, "html-start-tag.tmpl", map[string]string{
 		"lang": n.GetAttributeValueDefault("lang"),
 		"dir":  n.GetAttributeValueDefault("dir"),
@@ -68,7 +76,9 @@ func (m MJML) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
 	for _, child := range n.Children {
 		switch child.Type {
 		case HeadTagName:
-			m.preparseHeadMetaValues(ctx, child)
+			if err := m.preparseHeadMetaValues(ctx, child); err != nil {
+				return err
+			}
 			headNode = child
 		case BodyTagName:
 			m.setAttributeDefaults(ctx, child)
@@ -100,17 +110,27 @@ func (m MJML) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
 
 func (m MJML) setAttributeDefaults(ctx *RenderContext, n *node.Node) {
 	if strings.HasPrefix(n.Type, "mj-") {
+		// mj-class attributes take precedence over the tag defaults, later classes over earlier ones
+		classes := strings.Fields(n.GetAttributeValueDefault("mj-class"))
+		for _, class := range slices.Backward(classes) {
+			for _, attr := range ctx.ClassAttributes[class] {
+				if _, has := n.GetAttributeValue(node.AttributeName(attr)); !has {
+					n.SetAttribute(node.AttributeName(attr), attr.Value)
+				}
+			}
+		}
+
 		if n.Type == "mj-text" {
 			for _, attr := range ctx.GlobalTextAttributes {
-				if _, has := n.GetAttributeValue(attr.Name.Local); !has {
-					n.SetAttribute(attr.Name.Local, attr.Value)
+				if _, has := n.GetAttributeValue(node.AttributeName(attr)); !has {
+					n.SetAttribute(node.AttributeName(attr), attr.Value)
 				}
 			}
 		}
 
 		for _, attr := range ctx.GlobalAllAttributes {
-			if _, has := n.GetAttributeValue(attr.Name.Local); !has {
-				n.SetAttribute(attr.Name.Local, attr.Value)
+			if _, has := n.GetAttributeValue(node.AttributeName(attr)); !has {
+				n.SetAttribute(node.AttributeName(attr), attr.Value)
 			}
 		}
 
@@ -130,6 +150,17 @@ func (m MJML) preparseHeadMetaValues(ctx *RenderContext, n *node.Node) error {
 					ctx.GlobalTextAttributes = append(ctx.GlobalTextAttributes, nestedChild.Attributes...)
 				case AllTagName:
 					ctx.GlobalAllAttributes = append(ctx.GlobalAllAttributes, nestedChild.Attributes...)
+				case ClassTagName:
+					name, ok := nestedChild.GetAttributeValue("name")
+					if !ok {
+						continue
+					}
+					if ctx.ClassAttributes == nil {
+						ctx.ClassAttributes = make(map[string][]xml.Attr)
+					}
+					ctx.ClassAttributes[name] = slices.DeleteFunc(slices.Clone(nestedChild.Attributes), func(attr xml.Attr) bool {
+						return node.AttributeName(attr) == "name"
+					})
 				}
 			}
 		case BreakpointTagName:
@@ -141,7 +172,24 @@ func (m MJML) preparseHeadMetaValues(ctx *RenderContext, n *node.Node) error {
 		case FontTagName:
 			n, has := child.GetAttributeValue("name")
 			if !has {
-				return fmt.Errorf("%w: missing name in mj-font element", ErrValidation)
+				// like attribute validation, a nameless font is only fatal with strict validation
+				validationErr := &ValidationError{
+					Tag:       FontTagName,
+					Attribute: "name",
+					Line:      child.Line,
+					Column:    child.Column,
+					Err:       fmt.Errorf("%w: missing name", ErrValidation),
+				}
+
+				switch ctx.ValidationLevel {
+				case ValidationSoft:
+					ctx.ValidationErrors = append(ctx.ValidationErrors, validationErr)
+					continue
+				case ValidationSkip:
+					continue
+				}
+
+				return validationErr
 			}
 
 			ctx.Fonts[n] = child.GetAttributeValueDefault("href")
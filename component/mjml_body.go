@@ -2,6 +2,7 @@ package component
 
 import (
 	"fmt"
+	"html"
 	"io"
 
 	"github.com/julez-dev/mjmlgo/node"
@@ -27,6 +28,9 @@ func (b MJMLBody) DefaultAttributes(_ *RenderContext) map[string]string {
 }
 
 func (b MJMLBody) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	bodyInlineStyles := inlineStyle{
 		{Property: "word-spacing", Value: "normal"},
 	}
@@ -41,7 +45,7 @@ func (b MJMLBody) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
 
 	bodyDivName, _ := n.GetAttributeValue("css-class")
 
-	_, _ = io.WriteString(w, fmt.Sprintf("<body style=\"%s\">\n", bodyInlineStyles.InlineString()))
+	_, _ = io.WriteString(w, fmt.Sprintf("<body style=\"%s\">\n", html.EscapeString(bodyInlineStyles.InlineString())))
 	if ctx.PreviewText != "" {
 		if err := templates.ExecuteTemplate(w, "preview-text.tmpl", ctx.PreviewText); err != nil {
 			return err
@@ -55,45 +59,70 @@ func (b MJMLBody) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
 		"style": inlineStyle{{Property: "background-color", Value: n.GetAttributeValueDefault("background-color")}}.InlineString()}.InlineString()),
 	)
 
+	if err := runHooks(ctx, w, ctx.BeforeBodyHooks); err != nil {
+		return fmt.Errorf("error running before body hooks: %w", err)
+	}
+
 	for _, child := range n.Children {
-		switch child.Type {
-		case RawTagName:
-			var raw MJMLRaw
-			if err := InitComponent(ctx, raw, child); err != nil {
-				return err
-			}
-			if err := raw.Render(ctx, w, child); err != nil {
-				return err
-			}
-		case SectionTagName:
-			var section MJMLSection
-			if err := InitComponent(ctx, section, child); err != nil {
-				return err
-			}
-			if err := section.Render(ctx, w, child); err != nil {
-				return err
-			}
-		case WrapperTagName:
-			var section MJMLSection
-			section.IsWrapper = true
-			if err := InitComponent(ctx, section, child); err != nil {
-				return err
-			}
-			if err := section.Render(ctx, w, child); err != nil {
-				return err
-			}
-		case HeroTagName:
-			var hero MJMLHero
-			if err := InitComponent(ctx, hero, child); err != nil {
-				return err
-			}
-			if err := hero.Render(ctx, w, child); err != nil {
-				return err
-			}
+		cw := &countingWriter{w: w}
+		if err := b.renderChild(ctx, cw, child); err != nil {
+			return err
 		}
+
+		if cw.n > 0 {
+			ctx.SectionSizes = append(ctx.SectionSizes, SectionSize{
+				Type:     child.Type,
+				CSSClass: child.GetAttributeValueDefault("css-class"),
+				Bytes:    cw.n,
+			})
+		}
+	}
+
+	if err := runHooks(ctx, w, ctx.AfterBodyHooks); err != nil {
+		return fmt.Errorf("error running after body hooks: %w", err)
 	}
 
 	_, _ = io.WriteString(w, "</div>\n")
 	_, _ = io.WriteString(w, "</body>\n")
 	return nil
 }
+
+func (b MJMLBody) renderChild(ctx *RenderContext, w io.Writer, child *node.Node) error {
+	switch child.Type {
+	case RawTagName:
+		var raw MJMLRaw
+		if err := InitComponent(ctx, raw, child); err != nil {
+			return err
+		}
+		if err := raw.Render(ctx, w, child); err != nil {
+			return err
+		}
+	case SectionTagName:
+		var section MJMLSection
+		if err := InitComponent(ctx, section, child); err != nil {
+			return err
+		}
+		if err := section.Render(ctx, w, child); err != nil {
+			return err
+		}
+	case WrapperTagName:
+		var section MJMLSection
+		section.IsWrapper = true
+		if err := InitComponent(ctx, section, child); err != nil {
+			return err
+		}
+		if err := section.Render(ctx, w, child); err != nil {
+			return err
+		}
+	case HeroTagName:
+		var hero MJMLHero
+		if err := InitComponent(ctx, hero, child); err != nil {
+			return err
+		}
+		if err := hero.Render(ctx, w, child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
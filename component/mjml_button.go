@@ -72,6 +72,9 @@ func (b MJMLButton) DefaultAttributes(ctx *RenderContext) map[string]string {
 }
 
 func (b MJMLButton) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	styles, err := b.getStyles(ctx, n)
 	if err != nil {
 		return err
@@ -106,7 +109,7 @@ func (b MJMLButton) Render(ctx *RenderContext, w io.Writer, n *node.Node) error
 	}
 
 	if tag == "a" {
-		contentTagAttributes["href"] = n.GetAttributeValueDefault("href")
+		contentTagAttributes["href"] = ctx.rewriteLink(b.Name(), n, n.GetAttributeValueDefault("href"))
 		contentTagAttributes["name"] = n.GetAttributeValueDefault("name")
 		contentTagAttributes["rel"] = n.GetAttributeValueDefault("rel")
 		contentTagAttributes["title"] = n.GetAttributeValueDefault("title")
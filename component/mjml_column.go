@@ -49,6 +49,9 @@ func (c MJMLColumn) DefaultAttributes(_ *RenderContext) map[string]string {
 }
 
 func (c MJMLColumn) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	className, err := getColumnClass(ctx, n)
 	if err != nil {
 		return fmt.Errorf("failed to get column class: %w", err)
@@ -110,6 +113,14 @@ func (c MJMLColumn) renderColumn(ctx *RenderContext, w io.Writer, n *node.Node)
 	_, _ = io.WriteString(w, "<tbody>\n")
 
 	for _, child := range n.Children {
+		if child.Type == RawTagName {
+			var raw MJMLRaw
+			if err := raw.Render(ctx, w, child); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if !slices.Contains(c.allowedChildren(), child.Type) {
 			return fmt.Errorf("invalid child type %s in column, allowed types are: %v", child.Type, c.allowedChildren())
 		}
@@ -43,6 +43,9 @@ func (d MJMLDivider) DefaultAttributes(_ *RenderContext) map[string]string {
 }
 
 func (d MJMLDivider) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	styles, err := d.getStyles(ctx, n)
 	if err != nil {
 		return fmt.Errorf("failed to get styles: %w", err)
@@ -31,6 +31,9 @@ func (g MJMLGroup) DefaultAttributes(ctx *RenderContext) map[string]string {
 }
 
 func (g MJMLGroup) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	sibs := nonRawSiblings(n)
 	parentWidth, err := strconv.Atoi(RemoveNonNumeric(ctx.ContainerWidth))
 	if err != nil {
@@ -132,6 +135,14 @@ func (g MJMLGroup) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
 	_, _ = io.WriteString(w, "<!--[if mso | IE]><table "+tableAttr.InlineString()+"><tr><![endif]-->")
 
 	for _, child := range n.Children {
+		if child.Type == RawTagName {
+			var raw MJMLRaw
+			if err := raw.Render(ctx, w, child); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if child.Type != ColumnTagName {
 			continue
 		}
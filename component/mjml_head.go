@@ -2,6 +2,7 @@ package component
 
 import (
 	"fmt"
+	"html"
 	"io"
 
 	"github.com/aymerick/douceur/parser"
@@ -23,40 +24,17 @@ func (h MJMLHead) DefaultAttributes(_ *RenderContext) map[string]string {
 }
 
 func (h MJMLHead) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
-	var headStylesheets []Stylesheet
 	var title string
 	_, _ = io.WriteString(w, `<head>`)
 	for _, child := range n.Children {
-		switch child.Type {
-		case StyleTagName:
-			sheet, err := h.parseCSS(child.Content)
-			if err != nil {
-				return err
-			}
-
-			if v, found := child.GetAttributeValue("inline"); found && v == "inline" {
-				ctx.InlineStyles = append(ctx.InlineStyles, sheet)
-				continue
-			}
-
-			headStylesheets = append(headStylesheets, sheet)
-		case TitleTagName:
+		if child.Type == TitleTagName {
 			title = child.Content
 		}
 	}
 
-	data := map[string]any{
-		"Breakpoint":                  ctx.Breakpoint,
-		"MJMLStyles":                  ctx.MJMLStylesheet,
-		"UserStyles":                  headStylesheets,
-		"IncludeMobileFullWidthStyle": ctx.IncludeMobileFullWidthStyle,
-		"LowerBreakpoint":             ctx.makeLowerBreakpoint(),
-		"Fonts":                       ctx.Fonts,
-	}
-
-	_, _ = io.WriteString(w, fmt.Sprintf("<title>%s</title>\n", title))
-	if err := templates.ExecuteTemplate(w, "head-style-section.tmpl", data); err != nil {
-		return fmt.Errorf("error executing head-style-section template: %w", err)
+	_, _ = io.WriteString(w, fmt.Sprintf("<title>%s</title>\n", html.EscapeString(title)))
+	if err := h.renderStyles(ctx, w, n); err != nil {
+		return err
 	}
 
 	for _, child := range n.Children {
@@ -70,11 +48,53 @@ func (h MJMLHead) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
 
 	}
 
+	if err := runHooks(ctx, w, ctx.HeadHooks); err != nil {
+		return fmt.Errorf("error running head hooks: %w", err)
+	}
+
 	_, _ = io.WriteString(w, "</head>\n")
 
 	return nil
 }
 
+// renderStyles writes the meta tags and styles of the document, including the styles registered by components
+// and the <mj-style> children of n. Styles with inline="inline" are added to ctx.InlineStyles instead.
+func (h MJMLHead) renderStyles(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	var headStylesheets []Stylesheet
+	for _, child := range n.Children {
+		if child.Type != StyleTagName {
+			continue
+		}
+
+		sheet, err := h.parseCSS(child.Content)
+		if err != nil {
+			return err
+		}
+
+		if v, found := child.GetAttributeValue("inline"); found && v == "inline" {
+			ctx.InlineStyles = append(ctx.InlineStyles, sheet)
+			continue
+		}
+
+		headStylesheets = append(headStylesheets, sheet)
+	}
+
+	data := map[string]any{
+		"Breakpoint":                  ctx.Breakpoint,
+		"MJMLStyles":                  ctx.MJMLStylesheet,
+		"UserStyles":                  headStylesheets,
+		"IncludeMobileFullWidthStyle": ctx.IncludeMobileFullWidthStyle,
+		"LowerBreakpoint":             ctx.makeLowerBreakpoint(),
+		"Fonts":                       ctx.Fonts,
+	}
+
+	if err := templates.ExecuteTemplate(w, "head-style-section.tmpl", data); err != nil {
+		return fmt.Errorf("error executing head-style-section template: %w", err)
+	}
+
+	return nil
+}
+
 // ParseCSS takes a raw CSS string and parses it into a slice of Style structs.
 // It handles multiple CSS rules within the input string.
 func (h MJMLHead) parseCSS(cssText string) (Stylesheet, error) {
@@ -54,6 +54,9 @@ func (h MJMLHero) DefaultAttributes(ctx *RenderContext) map[string]string {
 }
 
 func (h MJMLHero) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	containerWidth := ctx.ContainerWidth
 
 	styles, err := h.getStyles(ctx, n)
@@ -272,7 +275,12 @@ func (h MJMLHero) renderContent(ctx *RenderContext, w io.Writer, n *node.Node) e
 		case TableTagName:
 			childComponent = MJMLTable{}
 		case RawTagName:
-			childComponent = MJMLRaw{}
+			// like upstream MJML, raw elements and comments are not wrapped in a table row
+			var raw MJMLRaw
+			if err := raw.Render(ctx, w, child); err != nil {
+				return err
+			}
+			continue
 		}
 
 		if childComponent == nil {
@@ -60,6 +60,9 @@ func (i MJMLImage) DefaultAttributes(_ *RenderContext) map[string]string {
 }
 
 func (i MJMLImage) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	styles, err := i.getStyles(ctx, n)
 	if err != nil {
 		return fmt.Errorf("failed to get styles: %w", err)
@@ -130,7 +133,7 @@ func (i MJMLImage) renderImage(ctx *RenderContext, w io.Writer, n *node.Node, im
 
 	if href, ok := n.GetAttributeValue("href"); ok {
 		aAttr := inlineAttributes{
-			"href":   href,
+			"href":   ctx.rewriteLink(i.Name(), n, href),
 			"target": n.GetAttributeValueDefault("target"),
 			"rel":    n.GetAttributeValueDefault("rel"),
 			"name":   n.GetAttributeValueDefault("name"),
@@ -14,7 +14,9 @@ func (r MJMLRaw) Name() string {
 }
 
 func (r MJMLRaw) AllowedAttributes() map[string]validateAttributeFunc {
-	return make(map[string]validateAttributeFunc)
+	return map[string]validateAttributeFunc{
+		"position": validateEnum([]string{"file-start"}),
+	}
 }
 
 func (r MJMLRaw) DefaultAttributes(_ *RenderContext) map[string]string {
@@ -22,6 +24,36 @@ func (r MJMLRaw) DefaultAttributes(_ *RenderContext) map[string]string {
 }
 
 func (r MJMLRaw) Render(_ *RenderContext, w io.Writer, n *node.Node) error {
+	// file-start content is collected by MJML.Render and written before the doctype
+	if isFileStartRaw(n) {
+		return nil
+	}
+
 	_, _ = io.WriteString(w, strings.TrimSpace(n.Content))
 	return nil
 }
+
+// isFileStartRaw reports whether n is an <mj-raw position="file-start"> element.
+func isFileStartRaw(n *node.Node) bool {
+	return n.Type == RawTagName && n.GetAttributeValueDefault("position") == "file-start"
+}
+
+// NewComment returns the node for an XML comment with the text text. Like upstream MJML, comments are kept as
+// mj-raw elements, so they are rendered in place.
+func NewComment(text string) *node.Node {
+	return &node.Node{Type: RawTagName, Content: "<!-- " + strings.TrimSpace(text) + " -->"}
+}
+
+// IsComment reports whether n is an mj-raw element holding nothing but a comment, as returned by NewComment.
+func IsComment(n *node.Node) bool {
+	if n.Type != RawTagName || len(n.Attributes) > 0 || len(n.Children) > 0 {
+		return false
+	}
+
+	text, ok := strings.CutPrefix(n.Content, "<!-- ")
+	if !ok {
+		return false
+	}
+	text, ok = strings.CutSuffix(text, " -->")
+	return ok && !strings.Contains(text, "-->") && text == strings.TrimSpace(text)
+}
@@ -57,6 +57,9 @@ func (s MJMLSection) DefaultAttributes(_ *RenderContext) map[string]string {
 }
 
 func (s MJMLSection) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	if v, ok := n.GetAttributeValue("full-width"); ok && v == "full-width" {
 		return s.renderFullWidth(ctx, w, n)
 	}
@@ -366,6 +369,11 @@ func (s MJMLSection) renderWrappedChildren(ctx *RenderContext, w io.Writer, n *n
 				if err := group.Render(ctx, w, child); err != nil {
 					return err
 				}
+			case RawTagName:
+				var raw MJMLRaw
+				if err := raw.Render(ctx, w, child); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -374,6 +382,14 @@ func (s MJMLSection) renderWrappedChildren(ctx *RenderContext, w io.Writer, n *n
 	}
 
 	for _, child := range n.Children {
+		if child.Type == RawTagName {
+			var raw MJMLRaw
+			if err := raw.Render(ctx, w, child); err != nil {
+				return err
+			}
+			continue
+		}
+
 		attr := inlineAttributes{
 			"align": child.GetAttributeValueDefault("align"),
 			"width": ctx.ContainerWidth,
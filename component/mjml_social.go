@@ -56,6 +56,9 @@ func (s MJMLSocial) DefaultAttributes(_ *RenderContext) map[string]string {
 }
 
 func (s MJMLSocial) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	if n.GetAttributeValueDefault("mode") == "horizontal" {
 		if err := s.renderHorizontal(ctx, w, n); err != nil {
 			return err
@@ -83,6 +86,14 @@ func (s MJMLSocial) renderHorizontal(ctx *RenderContext, w io.Writer, n *node.No
 	attr := s.getSocialElementAttributes(n)
 
 	for _, child := range n.Children {
+		if child.Type == RawTagName {
+			var raw MJMLRaw
+			if err := raw.Render(ctx, w, child); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if child.Type != SocialElementTagName {
 			continue
 		}
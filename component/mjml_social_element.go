@@ -181,6 +181,9 @@ func (s MJMLSocialElement) DefaultAttributes(_ *RenderContext) map[string]string
 }
 
 func (s MJMLSocialElement) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	attrs := s.getSocialAttributes(n)
 	styles := s.getStyles(n)
 	var (
@@ -197,6 +200,10 @@ func (s MJMLSocialElement) Render(ctx *RenderContext, w io.Writer, n *node.Node)
 	}
 
 	_, hasLink := n.GetAttributeValue("href")
+	if hasLink {
+		// the icon and the text link to the same URL, so the link is rewritten once
+		href = ctx.rewriteLink(s.Name(), n, href)
+	}
 	iconPosition := n.GetAttributeValueDefault("icon-position")
 
 	makeIcon := func() string {
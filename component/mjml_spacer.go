@@ -36,6 +36,9 @@ func (s MJMLSpacer) DefaultAttributes(ctx *RenderContext) map[string]string {
 }
 
 func (s MJMLSpacer) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	divStyle := inlineStyle{
 		{Property: "height", Value: n.GetAttributeValueDefault("height")},
 		{Property: "line-height", Value: n.GetAttributeValueDefault("height")},
@@ -54,6 +54,9 @@ func (t MJMLTable) DefaultAttributes(ctx *RenderContext) map[string]string {
 }
 
 func (t MJMLTable) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	attributeKeys := [...]string{"cellpadding", "cellspacing", "role"}
 	tableAttributes := make(inlineAttributes)
 
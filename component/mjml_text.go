@@ -2,6 +2,7 @@ package component
 
 import (
 	"fmt"
+	"html"
 	"io"
 
 	"github.com/julez-dev/mjmlgo/node"
@@ -49,10 +50,13 @@ func (t MJMLText) DefaultAttributes(ctx *RenderContext) map[string]string {
 }
 
 func (t MJMLText) Render(ctx *RenderContext, w io.Writer, n *node.Node) error {
+	w, flush := annotate(ctx, w, n)
+	defer flush()
+
 	height, has := n.GetAttributeValue("height")
 
 	if has {
-		_, _ = io.WriteString(w, conditionalTag(fmt.Sprintf("<table role=\"presentation\" border=\"0\" cellpadding=\"0\" cellspacing=\"0\"><tr><td height=\"%s\" style=\"vertical-align:top;height:%s;\">", height, height), false))
+		_, _ = io.WriteString(w, conditionalTag(fmt.Sprintf("<table role=\"presentation\" border=\"0\" cellpadding=\"0\" cellspacing=\"0\"><tr><td height=\"%s\" style=\"vertical-align:top;height:%s;\">", html.EscapeString(height), html.EscapeString(height)), false))
 		if err := t.renderContent(ctx, w, n); err != nil {
 			return err
 		}
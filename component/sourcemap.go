@@ -0,0 +1,73 @@
+package component
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/julez-dev/mjmlgo/node"
+)
+
+// SourceMapAttribute is added to the root element of each component when RenderContext.SourceMap is set.
+// Its value is the position of the MJML element in the source, e.g. data-mj-node="12:5" for line 12, column 5.
+const SourceMapAttribute = "data-mj-node"
+
+// annotate returns the writer a component renders n to and a function which must be called when rendering is done.
+// With RenderContext.SourceMap the output is buffered and flush writes it to w with a SourceMapAttribute
+// added to the first element, otherwise w is returned as is.
+func annotate(ctx *RenderContext, w io.Writer, n *node.Node) (io.Writer, func()) {
+	if !ctx.SourceMap || n.Line == 0 {
+		return w, func() {}
+	}
+
+	var buf bytes.Buffer
+	return &buf, func() {
+		_, _ = io.WriteString(w, addSourceMapAttribute(buf.String(), n))
+	}
+}
+
+// addSourceMapAttribute adds the SourceMapAttribute of n to the first start tag in s.
+// Comments, like the conditional comments for Outlook, are skipped. If the element already has the attribute,
+// because it is also the root element of a child component, the position of the child is kept.
+func addSourceMapAttribute(s string, n *node.Node) string {
+	for i := 0; i < len(s); i++ {
+		idx := strings.IndexByte(s[i:], '<')
+		if idx < 0 {
+			return s
+		}
+		i += idx
+
+		if strings.HasPrefix(s[i:], "<!--") {
+			end := strings.Index(s[i+4:], "-->")
+			if end < 0 {
+				return s
+			}
+			i += 4 + end + 2
+			continue
+		}
+
+		if i+1 >= len(s) || !isASCIILetter(s[i+1]) {
+			continue
+		}
+
+		nameEnd := strings.IndexAny(s[i+1:], " \t\r\n/>")
+		tagEnd := strings.IndexByte(s[i:], '>')
+		if nameEnd < 0 || tagEnd < 0 {
+			return s
+		}
+
+		if strings.Contains(s[i:i+tagEnd], " "+SourceMapAttribute+"=") {
+			return s
+		}
+
+		nameEnd += i + 1
+		return s[:nameEnd] + fmt.Sprintf(` %s="%d:%d"`, SourceMapAttribute, n.Line, n.Column) + s[nameEnd:]
+	}
+
+	return s
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
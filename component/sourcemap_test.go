@@ -0,0 +1,38 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/julez-dev/mjmlgo/node"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddSourceMapAttribute(t *testing.T) {
+	t.Parallel()
+
+	n := &node.Node{Type: "mj-section", Line: 3, Column: 5}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"element", `<div style="a">x</div>`, `<div data-mj-node="3:5" style="a">x</div>`},
+		{"no-attributes", `<p></p>`, `<p data-mj-node="3:5"></p>`},
+		{"self-closing", `<br/>`, `<br data-mj-node="3:5"/>`},
+		{
+			"conditional-comments",
+			`<!--[if mso | IE]><table><tr><td><![endif]--><div>x</div>`,
+			`<!--[if mso | IE]><table><tr><td><![endif]--><div data-mj-node="3:5">x</div>`,
+		},
+		{"child-position-kept", `<div data-mj-node="4:7">x</div>`, `<div data-mj-node="4:7">x</div>`},
+		{"text-only", `a < b`, `a < b`},
+		{"unterminated-comment", `<!-- <div>`, `<!-- <div>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, addSourceMapAttribute(tt.input, n))
+		})
+	}
+}
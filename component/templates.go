@@ -2,6 +2,8 @@ package component
 
 import (
 	"embed"
+	"fmt"
+	"strings"
 	"sync"
 	"text/template"
 )
@@ -16,7 +18,9 @@ var (
 
 func init() {
 	o.Do(func() {
-		t, err := template.ParseFS(templateFS, "texttemplate/*.tmpl")
+		t, err := template.New("").Funcs(template.FuncMap{
+			"cssString": cssString,
+		}).ParseFS(templateFS, "texttemplate/*.tmpl")
 		if err != nil {
 			panic(err)
 		}
@@ -24,3 +28,21 @@ func init() {
 		templates = t
 	})
 }
+
+// cssString quotes s as a CSS string. Quotes, backslashes, line breaks and angle brackets are written as escape
+// sequences, so the string can neither end early nor close the surrounding <style> element.
+func cssString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\', '<', '>', '\n', '\r', '\f':
+			fmt.Fprintf(&b, "\\%x ", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
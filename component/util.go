@@ -2,6 +2,8 @@ package component
 
 import (
 	"fmt"
+	"html"
+	"io"
 	"maps"
 	"math"
 	"regexp"
@@ -98,7 +100,7 @@ func (ia inlineAttributes) InlineString() string {
 		if value == "" {
 			continue
 		}
-		b.WriteString(fmt.Sprintf("%s=\"%s\" ", key, value))
+		b.WriteString(fmt.Sprintf("%s=\"%s\" ", key, html.EscapeString(value)))
 	}
 	return strings.TrimSpace(b.String())
 }
@@ -507,3 +509,15 @@ func makeBackgroundString(parts []string) string {
 
 	return strings.Join(filteredParts, " ")
 }
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
@@ -41,6 +41,14 @@ func TestInlineAttribute(t *testing.T) {
 		}
 		require.Equal(t, `cellpadding="10" class="my-class"`, s.InlineString())
 	})
+
+	t.Run("escaped", func(t *testing.T) {
+		s := inlineAttributes{
+			"alt":  `Say "hi" <b>`,
+			"href": "https://example.com/?a=1&b=2",
+		}
+		require.Equal(t, `alt="Say &#34;hi&#34; &lt;b&gt;" href="https://example.com/?a=1&amp;b=2"`, s.InlineString())
+	})
 }
 
 func TestIsPercentage(t *testing.T) {
@@ -11,6 +11,36 @@ import (
 
 var ErrValidation = errors.New("failed validation")
 
+// ValidationLevel controls how attribute validation errors are handled.
+type ValidationLevel string
+
+const (
+	// ValidationStrict aborts rendering on the first validation error. This is the default.
+	ValidationStrict ValidationLevel = "strict"
+	// ValidationSoft collects validation errors in RenderContext.ValidationErrors and keeps rendering.
+	ValidationSoft ValidationLevel = "soft"
+	// ValidationSkip does not validate attributes at all.
+	ValidationSkip ValidationLevel = "skip"
+)
+
+// ValidationError is returned when an attribute of a component fails validation.
+type ValidationError struct {
+	Tag       string
+	Attribute string
+	// Line and Column are the position of the element in the MJML source, 0 if unknown.
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("failed to validate field %s in <%s>: %s", e.Attribute, e.Tag, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
 var (
 	// Regex for different color formats
 	rgbaRegex = regexp.MustCompile(`(?i)^rgba\(\s*\d{1,3}\s*,\s*\d{1,3}\s*,\s*\d{1,3}\s*,\s*\d(\.\d+)?\s*\)$`)
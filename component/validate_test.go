@@ -1,8 +1,10 @@
 package component
 
 import (
+	"encoding/xml"
 	"testing"
 
+	"github.com/julez-dev/mjmlgo/node"
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,3 +50,43 @@ func TestValidateColort(t *testing.T) {
 		require.Error(t, f("FFFFF"))
 	})
 }
+
+func TestInitComponentValidationLevel(t *testing.T) {
+	t.Parallel()
+
+	newNode := func() *node.Node {
+		return &node.Node{
+			Line:   3,
+			Column: 5,
+			Attributes: []xml.Attr{
+				{Name: xml.Name{Local: "color"}, Value: "not-a-color"},
+				{Name: xml.Name{Local: "align"}, Value: "middle"},
+			},
+		}
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		err := InitComponent(&RenderContext{}, MJMLText{}, newNode())
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.ErrorIs(t, err, ErrValidation)
+		require.Equal(t, "align", validationErr.Attribute)
+		require.Equal(t, 3, validationErr.Line)
+		require.Equal(t, 5, validationErr.Column)
+	})
+
+	t.Run("soft", func(t *testing.T) {
+		ctx := &RenderContext{ValidationLevel: ValidationSoft}
+		require.NoError(t, InitComponent(ctx, MJMLText{}, newNode()))
+		require.Len(t, ctx.ValidationErrors, 2)
+		require.Equal(t, "align", ctx.ValidationErrors[0].Attribute)
+		require.Equal(t, "color", ctx.ValidationErrors[1].Attribute)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		ctx := &RenderContext{ValidationLevel: ValidationSkip}
+		require.NoError(t, InitComponent(ctx, MJMLText{}, newNode()))
+		require.Empty(t, ctx.ValidationErrors)
+	})
+}
@@ -209,3 +209,77 @@ func removeEmptyChildren(n *html.Node) {
 		n.RemoveChild(c)
 	}
 }
+
+// TestMJMLWhitespace compares the text content of the head and of mj-text elements with a css-class
+// starting with ws- exactly, TestMJMLFiles ignores text nodes.
+func TestMJMLWhitespace(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.ReadFile("testdata/whitespace.mjml")
+	require.NoError(t, err)
+
+	html1, err := RenderMJML(bytes.NewReader(f))
+	require.NoError(t, err)
+
+	html2, err := mjml.ToHTML(context.Background(), string(f))
+	require.NoError(t, err)
+
+	n1, err := html.Parse(strings.NewReader(html1))
+	require.NoError(t, err)
+
+	n2, err := html.Parse(strings.NewReader(html2))
+	require.NoError(t, err)
+
+	texts1, texts2 := whitespaceTexts(t, n1), whitespaceTexts(t, n2)
+	require.Len(t, texts1, 6)
+	require.Equal(t, texts2, texts1)
+}
+
+// whitespaceTexts returns the inner HTML of the title, the preview and the mj-text elements of testdata/whitespace.mjml.
+func whitespaceTexts(t *testing.T, n *html.Node) map[string]string {
+	t.Helper()
+
+	texts := make(map[string]string)
+	for c := range n.Descendants() {
+		if c.Type != html.ElementNode {
+			continue
+		}
+
+		switch {
+		case c.Data == "title":
+			texts["title"] = innerHTML(t, c)
+		case c.Data == "div" && c.Parent.Data == "body" && strings.HasPrefix(getAttr(c, "style"), "display:none"):
+			texts["preview"] = innerHTML(t, c)
+		case c.Data == "td" && strings.HasPrefix(getAttr(c, "class"), "ws-"):
+			for div := range c.ChildNodes() {
+				if div.Type == html.ElementNode {
+					texts[getAttr(c, "class")] = innerHTML(t, div)
+					break
+				}
+			}
+		}
+	}
+
+	return texts
+}
+
+func innerHTML(t *testing.T, n *html.Node) string {
+	t.Helper()
+
+	var b strings.Builder
+	for c := range n.ChildNodes() {
+		require.NoError(t, html.Render(&b, c))
+	}
+
+	return b.String()
+}
+
+func getAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
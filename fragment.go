@@ -0,0 +1,96 @@
+package mjmlgo
+
+import (
+	"io"
+	"strings"
+
+	"github.com/julez-dev/mjmlgo/component"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Fragment is a component rendered without a surrounding document by RenderFragment.
+type Fragment struct {
+	// HTML is the rendered markup of the component.
+	HTML string
+	// Head is the markup the component requires inside <head>, like the meta tags, the media queries of columns,
+	// fonts and the rules of mj-style.
+	Head string
+	// Removed lists the content removed by safe mode.
+	Removed []Removal
+	// ValidationErrors contains the validation errors collected with component.ValidationSoft.
+	ValidationErrors []*component.ValidationError
+	// Recovered contains the syntax errors the parser recovered from, see WithRecovery.
+	Recovered []*SyntaxError
+}
+
+// RenderFragment renders a single mj-section, mj-wrapper, mj-hero, mj-column or mj-group without an <mjml> document,
+// e.g. for a gallery of components. The width available to the component is set with WithContainerWidth and
+// the <mj-head> applied to it with WithHead.
+//
+// Hooks added with WithHeadHook write into Fragment.Head. WithBeforeBodyHook, WithAfterBodyHook and WithSizeBudget
+// have no effect, a fragment has no body and is meant to be embedded into a larger document.
+func RenderFragment(input io.Reader, opts ...Option) (*Fragment, error) {
+	options := newRenderOptions(opts)
+
+	root, recovered, err := parse(input, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []Removal
+	if options.safeMode != nil {
+		if removed, err = sanitize(root, *options.safeMode); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := newRenderContext(options)
+	ctx.ContainerWidth = options.containerWidth
+
+	var body, head strings.Builder
+	if err := component.RenderFragment(ctx, &body, &head, options.head, root); err != nil {
+		return nil, err
+	}
+
+	result, err := inlineFragmentCSS(ctx, body.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fragment{
+		HTML:             duplicateConditionalComments.ReplaceAllString(result, ""),
+		Head:             head.String(),
+		Removed:          removed,
+		ValidationErrors: ctx.ValidationErrors,
+		Recovered:        recovered,
+	}, nil
+}
+
+// inlineFragmentCSS applies the inline stylesheets of ctx to the HTML fragment s like inlineCSS does for documents.
+func inlineFragmentCSS(ctx *component.RenderContext, s string) (string, error) {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+
+	shielded, spans := shieldRawText(s)
+	nodes, err := html.ParseFragment(strings.NewReader(shielded), body)
+	if err != nil {
+		return "", err
+	}
+
+	for _, n := range nodes {
+		body.AppendChild(n)
+	}
+
+	if _, err := applyInlineStyles(ctx, body); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for n := range body.ChildNodes() {
+		if err := html.Render(&b, n); err != nil {
+			return "", err
+		}
+	}
+
+	return restoreRawText(b.String(), spans), nil
+}
@@ -0,0 +1,111 @@
+package mjmlgo
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/julez-dev/mjmlgo/component"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderFragment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("section", func(t *testing.T) {
+		const section = `<mj-section background-color="#eeeeee">
+  <mj-column><mj-text>Left</mj-text></mj-column>
+  <mj-column><mj-image src="https://example.com/a.png" /></mj-column>
+</mj-section>`
+
+		fragment, err := RenderFragment(strings.NewReader(section))
+		require.NoError(t, err)
+
+		document, err := RenderMJML(strings.NewReader("<mjml><mj-body>" + section + "</mj-body></mjml>"))
+		require.NoError(t, err)
+
+		require.Contains(t, document, fragment.HTML)
+		require.Contains(t, fragment.Head, ".mj-column-per-50 {")
+		require.Contains(t, fragment.Head, "table.mj-full-width-mobile")
+		require.NotContains(t, fragment.Head, "<head>")
+		require.NotContains(t, fragment.Head, "<title>")
+	})
+
+	t.Run("column-with-head", func(t *testing.T) {
+		head, err := Parse(strings.NewReader(`<mj-head>
+  <mj-breakpoint width="320px" />
+  <mj-attributes><mj-text color="#ff0000" /></mj-attributes>
+  <mj-style inline="inline">.bold { font-weight: bold; }</mj-style>
+  <mj-style>.blue { color: blue; }</mj-style>
+</mj-head>`))
+		require.NoError(t, err)
+
+		const column = `<mj-column width="50%"><mj-text css-class="bold">Hello</mj-text></mj-column>`
+
+		fragment, err := RenderFragment(strings.NewReader(column), WithContainerWidth("300px"), WithHead(head))
+		require.NoError(t, err)
+
+		require.True(t, strings.HasPrefix(fragment.HTML, `<div class="mj-column-per-50 mj-outlook-group-fix"`))
+		require.Contains(t, fragment.HTML, "color:#ff0000;")
+		require.Contains(t, fragment.HTML, "font-weight:bold;")
+		require.Contains(t, fragment.Head, "@media only screen and (min-width:320px)")
+		require.Contains(t, fragment.Head, ".blue {")
+
+		// the head can be used for further fragments
+		fragment, err = RenderFragment(strings.NewReader(column), WithHead(head))
+		require.NoError(t, err)
+		require.Contains(t, fragment.HTML, "color:#ff0000;")
+	})
+
+	t.Run("safe-mode", func(t *testing.T) {
+		fragment, err := RenderFragment(strings.NewReader(`<mj-column><mj-button href="javascript:alert(1)">Buy</mj-button></mj-column>`),
+			WithSafeMode(SafePolicy{}))
+		require.NoError(t, err)
+
+		require.NotContains(t, fragment.HTML, "javascript:")
+		require.Len(t, fragment.Removed, 1)
+		require.Equal(t, "mj-button", fragment.Removed[0].Tag)
+	})
+
+	t.Run("hooks-and-validation", func(t *testing.T) {
+		fragment, err := RenderFragment(strings.NewReader(`<mj-column><mj-text color="not-a-color">Hello</mj-text></mj-column>`),
+			WithValidationLevel(component.ValidationSoft),
+			WithHeadHook(func(_ *component.RenderContext, w io.Writer) error {
+				_, err := io.WriteString(w, `<meta name="x-campaign" content="42">`)
+				return err
+			}))
+		require.NoError(t, err)
+
+		require.True(t, strings.HasSuffix(fragment.Head, `<meta name="x-campaign" content="42">`))
+		require.Len(t, fragment.ValidationErrors, 1)
+	})
+
+	t.Run("nameless-font", func(t *testing.T) {
+		const head = `<mj-head><mj-font href="https://example.com/font.css" /></mj-head>`
+
+		headNode, err := Parse(strings.NewReader(head))
+		require.NoError(t, err)
+		_, err = RenderFragment(strings.NewReader(`<mj-column></mj-column>`), WithHead(headNode))
+		require.ErrorIs(t, err, component.ErrValidation)
+
+		_, err = RenderMJML(strings.NewReader(`<mjml>` + head + `<mj-body></mj-body></mjml>`))
+		require.ErrorIs(t, err, component.ErrValidation)
+	})
+
+	t.Run("recovery", func(t *testing.T) {
+		fragment, err := RenderFragment(strings.NewReader(`<mj-column><mj-text>Hello`), WithRecovery())
+		require.NoError(t, err)
+
+		require.Contains(t, fragment.HTML, "Hello")
+		require.NotEmpty(t, fragment.Recovered)
+		require.Equal(t, "<mj-text> is not closed", fragment.Recovered[0].Msg)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := RenderFragment(strings.NewReader(`<mj-text>Hello</mj-text>`))
+		require.ErrorIs(t, err, component.ErrMJMLBadlyFormatted)
+
+		_, err = RenderFragment(strings.NewReader(`<mjml><mj-body></mj-body></mjml>`))
+		require.ErrorIs(t, err, component.ErrMJMLBadlyFormatted)
+	})
+}
@@ -3,6 +3,7 @@ module github.com/julez-dev/mjmlgo
 go 1.24.0
 
 require (
+	github.com/Boostport/mjml-go v0.16.0
 	github.com/aymerick/douceur v0.2.0
 	github.com/ericchiang/css v1.4.0
 	github.com/stretchr/testify v1.10.0
@@ -10,7 +11,6 @@ require (
 )
 
 require (
-	github.com/Boostport/mjml-go v0.16.0 // indirect
 	github.com/andybalholm/brotli v1.2.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/documize/html-diff v0.0.0-20160503140253-f61c192c7796 // indirect
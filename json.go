@@ -0,0 +1,40 @@
+package mjmlgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/julez-dev/mjmlgo/node"
+)
+
+// ParseJSON builds a node tree from the MJML JSON format
+// ({"tagName": "mjml", "attributes": {...}, "children": [...], "content": "..."}) used by visual editors.
+func ParseJSON(input io.Reader) (*node.Node, error) {
+	var root node.Node
+	if err := json.NewDecoder(input).Decode(&root); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParsingFailed, err)
+	}
+
+	// like in Parse, fragments like a single mj-section get no head
+	if root.Type == "mjml" {
+		ensureHead(&root)
+	}
+
+	return &root, nil
+}
+
+// RenderJSON renders a document in the MJML JSON format to HTML.
+func RenderJSON(input io.Reader, opts ...Option) (string, error) {
+	root, err := ParseJSON(input)
+	if err != nil {
+		return "", err
+	}
+
+	out, _, err := render(root, newRenderOptions(opts))
+	if err != nil {
+		return "", err
+	}
+
+	return out, nil
+}
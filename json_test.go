@@ -0,0 +1,75 @@
+package mjmlgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("simple", func(t *testing.T) {
+		const input = `{
+			"tagName": "mjml",
+			"attributes": {"lang": "en"},
+			"children": [{
+				"tagName": "mj-body",
+				"children": [{
+					"tagName": "mj-section",
+					"children": [{
+						"tagName": "mj-column",
+						"children": [
+							{"tagName": "mj-text", "attributes": {"color": "#ff0000"}, "content": "<b>Hello</b>"}
+						]
+					}]
+				}]
+			}]
+		}`
+
+		out, err := RenderJSON(strings.NewReader(input))
+		require.NoError(t, err)
+		require.Contains(t, out, `lang="en"`)
+		require.Contains(t, out, "<b>Hello</b>")
+		require.Contains(t, out, "color:#ff0000;")
+	})
+
+	t.Run("same-as-xml", func(t *testing.T) {
+		input, err := os.ReadFile("testdata/button-complex.mjml")
+		require.NoError(t, err)
+
+		n, err := Parse(bytes.NewReader(input))
+		require.NoError(t, err)
+
+		b, err := json.Marshal(n)
+		require.NoError(t, err)
+
+		fromJSON, err := RenderJSON(bytes.NewReader(b))
+		require.NoError(t, err)
+
+		fromXML, err := RenderMJML(bytes.NewReader(input))
+		require.NoError(t, err)
+
+		require.Equal(t, fromXML, fromJSON)
+	})
+
+	t.Run("fragment", func(t *testing.T) {
+		root, err := ParseJSON(strings.NewReader(`{"tagName": "mj-section", "children": [{"tagName": "mj-column"}]}`))
+		require.NoError(t, err)
+
+		require.Len(t, root.Children, 1)
+		require.Equal(t, "mj-column", root.Children[0].Type)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := RenderJSON(strings.NewReader(`{"tagName": 1}`))
+		require.ErrorIs(t, err, ErrParsingFailed)
+
+		_, err = RenderJSON(strings.NewReader(`{"tagName": "mj-body"}`))
+		require.ErrorIs(t, err, ErrUnknownStartingTag)
+	})
+}
@@ -0,0 +1,183 @@
+package lint
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/julez-dev/mjmlgo/component"
+	"github.com/julez-dev/mjmlgo/node"
+	"golang.org/x/net/html"
+)
+
+const (
+	RuleImageAlt      = "image-alt"
+	RuleButtonLabel   = "button-label"
+	RuleColorContrast = "color-contrast"
+	RuleDocumentLang  = "document-lang"
+	RuleFontSize      = "font-size"
+)
+
+const (
+	defaultMinContrastRatio = 4.5
+	defaultMinFontSize      = 12
+	defaultBackgroundColor  = "#ffffff"
+)
+
+// AccessibilityOptions configures the thresholds used by Accessibility.
+// Zero values use the defaults.
+type AccessibilityOptions struct {
+	// MinContrastRatio is the minimum WCAG contrast ratio between text and background color, defaults to 4.5.
+	MinContrastRatio float64
+	// MinFontSize is the minimum font size in pixels, defaults to 12.
+	MinFontSize int
+}
+
+// textComponents are the components rendering text with their color and font-size attributes.
+var textComponents = map[string]struct{}{
+	component.TextTagName:          {},
+	component.ButtonTagName:        {},
+	component.TableTagName:         {},
+	component.SocialElementTagName: {},
+}
+
+// Accessibility checks the MJML tree for common accessibility problems.
+func Accessibility(root *node.Node, opts AccessibilityOptions) []Finding {
+	if opts.MinContrastRatio == 0 {
+		opts.MinContrastRatio = defaultMinContrastRatio
+	}
+	if opts.MinFontSize == 0 {
+		opts.MinFontSize = defaultMinFontSize
+	}
+
+	var (
+		findings []Finding
+		resolver = newAttributeResolver(root)
+	)
+
+	if root.Type == component.MJMLTagName {
+		if lang := root.GetAttributeValueDefault("lang"); lang == "" || lang == "und" {
+			findings = append(findings, newFinding(root, RuleDocumentLang, "<mjml> has no lang attribute"))
+		}
+	}
+
+	root.Walk(func(n *node.Node) bool {
+		switch n.Type {
+		case component.HeadTagName:
+			return false
+		case component.ImageTagName:
+			if _, has := n.GetAttributeValue("alt"); !has {
+				findings = append(findings, newFinding(n, RuleImageAlt, "<mj-image> has no alt attribute, use alt=\"\" for decorative images"))
+			}
+		case component.ButtonTagName:
+			if _, has := n.GetAttributeValue("href"); has && accessibleText(n.Content) == "" {
+				findings = append(findings, newFinding(n, RuleButtonLabel, "<mj-button> with href has no label"))
+			}
+		}
+
+		if _, ok := textComponents[n.Type]; !ok {
+			return true
+		}
+
+		if f, ok := checkContrast(resolver, n, opts.MinContrastRatio); ok {
+			findings = append(findings, f)
+		}
+
+		if fontSize, ok := resolver.value(n, "font-size"); ok && strings.HasSuffix(fontSize, "px") {
+			size, err := strconv.ParseFloat(strings.TrimSuffix(fontSize, "px"), 64)
+			if err == nil && size < float64(opts.MinFontSize) {
+				findings = append(findings, newFinding(n, RuleFontSize, "<%s> font-size %s is below %dpx", n.Type, fontSize, opts.MinFontSize))
+			}
+		}
+
+		return true
+	})
+
+	return findings
+}
+
+func checkContrast(resolver attributeResolver, n *node.Node, minRatio float64) (Finding, bool) {
+	colorValue, ok := resolver.value(n, "color")
+	if !ok {
+		return Finding{}, false
+	}
+
+	backgroundValue, ok := backgroundColor(resolver, n)
+	if !ok {
+		return Finding{}, false
+	}
+
+	color, ok := parseColor(colorValue)
+	if !ok {
+		return Finding{}, false
+	}
+
+	background, ok := parseColor(backgroundValue)
+	if !ok {
+		return Finding{}, false
+	}
+
+	if ratio := contrastRatio(color, background); ratio < minRatio {
+		return newFinding(n, RuleColorContrast, "<%s> contrast ratio %.2f between color %s and background %s is below %.1f", n.Type, ratio, colorValue, backgroundValue, minRatio), true
+	}
+
+	return Finding{}, false
+}
+
+// backgroundColor returns the nearest background color behind n.
+// It reports false if the background is an image, since the contrast can not be determined.
+func backgroundColor(resolver attributeResolver, n *node.Node) (string, bool) {
+	for current := n; current != nil; current = current.Parent {
+		var attrs []string
+		switch current.Type {
+		case component.ButtonTagName:
+			attrs = []string{"background-color"}
+		case component.TextTagName, component.TableTagName, component.SocialTagName:
+			attrs = []string{"container-background-color"}
+		case component.ColumnTagName:
+			attrs = []string{"inner-background-color", "background-color"}
+		case component.SectionTagName, component.WrapperTagName, component.HeroTagName:
+			if _, has := current.GetAttributeValue("background-url"); has {
+				return "", false
+			}
+			attrs = []string{"background-color"}
+		case component.BodyTagName, component.GroupTagName:
+			attrs = []string{"background-color"}
+		}
+
+		for _, attr := range attrs {
+			if v, ok := resolver.value(current, attr); ok && v != "" && v != "none" && v != "transparent" {
+				return v, true
+			}
+		}
+	}
+
+	return defaultBackgroundColor, true
+}
+
+// accessibleText returns the text of an HTML fragment as read by a screen reader,
+// including the alt text of images.
+func accessibleText(fragment string) string {
+	var b strings.Builder
+
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(b.String())
+		case html.TextToken:
+			b.Write(z.Text())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			if string(name) != "img" {
+				continue
+			}
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				if string(key) == "alt" {
+					b.Write(val)
+				}
+			}
+		}
+	}
+}
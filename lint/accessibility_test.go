@@ -0,0 +1,90 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julez-dev/mjmlgo"
+	"github.com/stretchr/testify/require"
+)
+
+func rules(findings []Finding) []string {
+	r := make([]string, 0, len(findings))
+	for _, f := range findings {
+		r = append(r, f.Rule)
+	}
+	return r
+}
+
+func TestAccessibility(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clean", func(t *testing.T) {
+		const input = `<mjml lang="en">
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-image src="https://example.com/a.png" alt="Logo"></mj-image>
+        <mj-text>Hello</mj-text>
+        <mj-button href="https://example.com">Buy</mj-button>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+		n, err := mjmlgo.Parse(strings.NewReader(input))
+		require.NoError(t, err)
+		require.Empty(t, Accessibility(n, AccessibilityOptions{}))
+	})
+
+	t.Run("problems", func(t *testing.T) {
+		const input = `<mjml>
+  <mj-head>
+    <mj-attributes>
+      <mj-text color="#777777"></mj-text>
+    </mj-attributes>
+  </mj-head>
+  <mj-body>
+    <mj-section background-color="#888888">
+      <mj-column>
+        <mj-image src="https://example.com/a.png"></mj-image>
+        <mj-text font-size="10px" color="#000000">Small</mj-text>
+        <mj-text>Grey on grey</mj-text>
+        <mj-button href="https://example.com"> </mj-button>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+		n, err := mjmlgo.Parse(strings.NewReader(input))
+		require.NoError(t, err)
+
+		findings := Accessibility(n, AccessibilityOptions{})
+		require.Equal(t, []string{RuleDocumentLang, RuleImageAlt, RuleFontSize, RuleColorContrast, RuleButtonLabel}, rules(findings))
+
+		require.Equal(t, 1, findings[0].Line)
+		require.Equal(t, 10, findings[1].Line)
+		require.Equal(t, 9, findings[1].Column)
+		require.Equal(t, 12, findings[3].Line)
+	})
+
+	t.Run("image-label", func(t *testing.T) {
+		require.Equal(t, "Buy now", accessibleText(`<img src="a.png" alt="Buy now" />`))
+		require.Equal(t, "", accessibleText(` <span></span> `))
+	})
+}
+
+func TestContrastRatio(t *testing.T) {
+	t.Parallel()
+
+	black, ok := parseColor("#000")
+	require.True(t, ok)
+	white, ok := parseColor("rgb(255, 255, 255)")
+	require.True(t, ok)
+
+	require.InDelta(t, 21, contrastRatio(black, white), 0.01)
+	require.InDelta(t, 1, contrastRatio(white, white), 0.01)
+
+	_, ok = parseColor("currentColor")
+	require.False(t, ok)
+}
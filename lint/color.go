@@ -0,0 +1,102 @@
+package lint
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type rgb struct {
+	r, g, b float64
+}
+
+var rgbFuncRegex = regexp.MustCompile(`(?i)^rgba?\(\s*(\d{1,3})\s*,\s*(\d{1,3})\s*,\s*(\d{1,3})\s*(?:,\s*[\d.]+\s*)?\)$`)
+
+// namedColors contains the values of commonly used CSS color names.
+var namedColors = map[string]string{
+	"black":   "#000000",
+	"white":   "#ffffff",
+	"red":     "#ff0000",
+	"green":   "#008000",
+	"blue":    "#0000ff",
+	"yellow":  "#ffff00",
+	"orange":  "#ffa500",
+	"purple":  "#800080",
+	"gray":    "#808080",
+	"grey":    "#808080",
+	"silver":  "#c0c0c0",
+	"maroon":  "#800000",
+	"navy":    "#000080",
+	"teal":    "#008080",
+	"olive":   "#808000",
+	"lime":    "#00ff00",
+	"aqua":    "#00ffff",
+	"fuchsia": "#ff00ff",
+}
+
+// parseColor parses a hex, rgb() or rgba() color or a common color name.
+// The alpha channel is ignored.
+func parseColor(s string) (rgb, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if named, ok := namedColors[s]; ok {
+		s = named
+	}
+
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		switch len(hex) {
+		case 3, 4:
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		case 6, 8:
+			hex = hex[:6]
+		default:
+			return rgb{}, false
+		}
+
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return rgb{}, false
+		}
+
+		return rgb{r: float64(v >> 16 & 0xff), g: float64(v >> 8 & 0xff), b: float64(v & 0xff)}, true
+	}
+
+	matches := rgbFuncRegex.FindStringSubmatch(s)
+	if matches == nil {
+		return rgb{}, false
+	}
+
+	var c [3]float64
+	for i := range c {
+		v, err := strconv.Atoi(matches[i+1])
+		if err != nil || v > 255 {
+			return rgb{}, false
+		}
+		c[i] = float64(v)
+	}
+
+	return rgb{r: c[0], g: c[1], b: c[2]}, true
+}
+
+// relativeLuminance implements the WCAG 2 definition of relative luminance.
+func (c rgb) relativeLuminance() float64 {
+	channel := func(v float64) float64 {
+		v /= 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+
+	return 0.2126*channel(c.r) + 0.7152*channel(c.g) + 0.0722*channel(c.b)
+}
+
+// contrastRatio returns the WCAG 2 contrast ratio between two colors, ranging from 1 to 21.
+func contrastRatio(a, b rgb) float64 {
+	l1, l2 := a.relativeLuminance(), b.relativeLuminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+
+	return (l1 + 0.05) / (l2 + 0.05)
+}
@@ -0,0 +1,230 @@
+package lint
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/aymerick/douceur/css"
+	"github.com/aymerick/douceur/parser"
+	"github.com/julez-dev/mjmlgo/component"
+	"github.com/julez-dev/mjmlgo/node"
+	"golang.org/x/net/html"
+)
+
+//go:embed compatibility.json
+var compatibilityJSON []byte
+
+// compatibilityData lists HTML and CSS features with poor support in email clients.
+type compatibilityData struct {
+	CSS  []cssFeature  `json:"css"`
+	HTML []htmlFeature `json:"html"`
+}
+
+type cssFeature struct {
+	ID      string   `json:"id"`
+	Feature string   `json:"feature"`
+	Clients []string `json:"clients"`
+
+	Property       string   `json:"property"`
+	PropertyPrefix string   `json:"property_prefix"`
+	Values         []string `json:"values"`
+	ValueContains  string   `json:"value_contains"`
+	AtRule         string   `json:"at_rule"`
+}
+
+type htmlFeature struct {
+	ID      string   `json:"id"`
+	Feature string   `json:"feature"`
+	Clients []string `json:"clients"`
+
+	Element   string   `json:"element"`
+	Attribute string   `json:"attribute"`
+	Values    []string `json:"values"`
+}
+
+var (
+	compatibility     compatibilityData
+	compatibilityOnce = &sync.Once{}
+)
+
+func loadCompatibility() compatibilityData {
+	compatibilityOnce.Do(func() {
+		if err := json.Unmarshal(compatibilityJSON, &compatibility); err != nil {
+			panic(err)
+		}
+	})
+
+	return compatibility
+}
+
+// Compatibility checks the content of <mj-raw> and <mj-style> for HTML and CSS features
+// which are not supported by major email clients.
+func Compatibility(root *node.Node) []Finding {
+	var (
+		findings []Finding
+		data     = loadCompatibility()
+	)
+
+	root.Walk(func(n *node.Node) bool {
+		switch n.Type {
+		case component.RawTagName:
+			findings = append(findings, checkHTML(data, n, n.Content)...)
+		case component.StyleTagName:
+			findings = append(findings, checkStylesheet(data, n, n.Content)...)
+		}
+
+		return true
+	})
+
+	return findings
+}
+
+func checkHTML(data compatibilityData, n *node.Node, content string) []Finding {
+	var findings []Finding
+
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return findings
+		}
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		token := z.Token()
+
+		for _, feature := range data.HTML {
+			if feature.Element == token.Data && matchesAttribute(feature, token.Attr) {
+				findings = append(findings, newCompatibilityFinding(n, feature.ID, feature.Feature, feature.Clients))
+			}
+		}
+
+		for _, attr := range token.Attr {
+			if attr.Key == "style" {
+				findings = append(findings, checkDeclarations(data, n, parseInlineDeclarations(attr.Val))...)
+			}
+		}
+
+		if token.Data == "style" && tt == html.StartTagToken && z.Next() == html.TextToken {
+			findings = append(findings, checkStylesheet(data, n, string(z.Text()))...)
+		}
+	}
+}
+
+func matchesAttribute(feature htmlFeature, attrs []html.Attribute) bool {
+	if feature.Attribute == "" {
+		return true
+	}
+
+	for _, attr := range attrs {
+		if attr.Key != feature.Attribute {
+			continue
+		}
+
+		if len(feature.Values) == 0 {
+			return true
+		}
+
+		for _, v := range feature.Values {
+			if strings.EqualFold(strings.TrimSpace(attr.Val), v) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func checkStylesheet(data compatibilityData, n *node.Node, content string) []Finding {
+	sheet, err := parser.Parse(content)
+	if err != nil {
+		return nil
+	}
+
+	var findings []Finding
+	var checkRules func(rules []*css.Rule)
+	checkRules = func(rules []*css.Rule) {
+		for _, rule := range rules {
+			if rule.Kind == css.AtRule {
+				for _, feature := range data.CSS {
+					if feature.AtRule != "" && strings.EqualFold(feature.AtRule, rule.Name) {
+						findings = append(findings, newCompatibilityFinding(n, feature.ID, feature.Feature, feature.Clients))
+					}
+				}
+			}
+
+			findings = append(findings, checkDeclarations(data, n, rule.Declarations)...)
+			checkRules(rule.Rules)
+		}
+	}
+	checkRules(sheet.Rules)
+
+	return findings
+}
+
+func checkDeclarations(data compatibilityData, n *node.Node, declarations []*css.Declaration) []Finding {
+	var findings []Finding
+
+	for _, dec := range declarations {
+		property := strings.ToLower(strings.TrimSpace(dec.Property))
+		value := strings.ToLower(strings.TrimSpace(dec.Value))
+
+		for _, feature := range data.CSS {
+			if matchesDeclaration(feature, property, value) {
+				findings = append(findings, newCompatibilityFinding(n, feature.ID, feature.Feature, feature.Clients))
+			}
+		}
+	}
+
+	return findings
+}
+
+func matchesDeclaration(feature cssFeature, property, value string) bool {
+	switch {
+	case feature.Property != "":
+		if property != feature.Property {
+			return false
+		}
+		if len(feature.Values) == 0 {
+			return true
+		}
+		for _, v := range feature.Values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	case feature.PropertyPrefix != "":
+		return strings.HasPrefix(property, feature.PropertyPrefix)
+	case feature.ValueContains != "":
+		return strings.Contains(value, feature.ValueContains)
+	}
+
+	return false
+}
+
+// parseInlineDeclarations parses the declarations of a style attribute.
+func parseInlineDeclarations(style string) []*css.Declaration {
+	var declarations []*css.Declaration
+	for declaration := range strings.SplitSeq(style, ";") {
+		property, value, found := strings.Cut(declaration, ":")
+		if !found {
+			continue
+		}
+
+		declarations = append(declarations, &css.Declaration{
+			Property: strings.TrimSpace(property),
+			Value:    strings.TrimSpace(value),
+		})
+	}
+
+	return declarations
+}
+
+func newCompatibilityFinding(n *node.Node, id, feature string, clients []string) Finding {
+	return newFinding(n, id, "<%s> uses %s, which is not supported by %s", n.Type, feature, strings.Join(clients, ", "))
+}
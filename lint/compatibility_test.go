@@ -0,0 +1,59 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julez-dev/mjmlgo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompatibility(t *testing.T) {
+	t.Parallel()
+
+	t.Run("supported", func(t *testing.T) {
+		const input = `<mjml>
+  <mj-head>
+    <mj-style>.title { color: red; display: block; }</mj-style>
+  </mj-head>
+  <mj-body>
+    <mj-raw><table><tr><td style="padding: 4px">Raw</td></tr></table></mj-raw>
+  </mj-body>
+</mjml>`
+
+		n, err := mjmlgo.Parse(strings.NewReader(input))
+		require.NoError(t, err)
+		require.Empty(t, Compatibility(n))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		const input = `<mjml>
+  <mj-head>
+    <mj-style>
+      @import url(https://example.com/fonts.css);
+      :root { --brand: #ff0000; }
+      .box { display: grid; color: var(--brand); }
+      @media (max-width: 480px) { .box { position: absolute; } }
+    </mj-style>
+  </mj-head>
+  <mj-body>
+    <mj-raw><div style="display: flex"><script>alert(1)</script><form action="/"></form></div></mj-raw>
+    <mj-raw><link rel="stylesheet" href="https://example.com/a.css"></mj-raw>
+  </mj-body>
+</mjml>`
+
+		n, err := mjmlgo.Parse(strings.NewReader(input))
+		require.NoError(t, err)
+
+		findings := Compatibility(n)
+		require.ElementsMatch(t, []string{
+			"css-import", "css-variables", "css-grid", "css-variables", "css-position",
+			"html-script", "html-form", "css-flexbox", "html-external-stylesheet",
+		}, rules(findings))
+
+		for _, f := range findings {
+			require.NotZero(t, f.Line)
+			require.Contains(t, f.Message, "not supported by")
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/julez-dev/mjmlgo/component"
+	"github.com/julez-dev/mjmlgo/node"
+)
+
+// Finding is a single problem reported by a check.
+type Finding struct {
+	// Rule is the identifier of the check that reported the finding, e.g. image-alt.
+	Rule    string
+	Message string
+	// Line and Column are the position of the offending element in the MJML source, 0 if unknown.
+	Line   int
+	Column int
+	Node   *node.Node `json:"-"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%d:%d: %s (%s)", f.Line, f.Column, f.Message, f.Rule)
+}
+
+func newFinding(n *node.Node, rule, format string, args ...any) Finding {
+	return Finding{
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+		Line:    n.Line,
+		Column:  n.Column,
+		Node:    n,
+	}
+}
+
+// attributeResolver looks up attribute values the same way they are resolved when rendering:
+// attributes on the element, <mj-attributes> in the head and the component defaults.
+type attributeResolver struct {
+	text map[string]string
+	all  map[string]string
+}
+
+func newAttributeResolver(root *node.Node) attributeResolver {
+	r := attributeResolver{
+		text: make(map[string]string),
+		all:  make(map[string]string),
+	}
+
+	for _, child := range root.Children {
+		if child.Type != component.HeadTagName {
+			continue
+		}
+
+		for _, headChild := range child.Children {
+			if headChild.Type != component.AttributesTagName {
+				continue
+			}
+
+			for _, attrs := range headChild.Children {
+				switch attrs.Type {
+				case component.TextTagName:
+					for _, attr := range attrs.Attributes {
+						r.text[node.AttributeName(attr)] = attr.Value
+					}
+				case component.AllTagName:
+					for _, attr := range attrs.Attributes {
+						r.all[node.AttributeName(attr)] = attr.Value
+					}
+				}
+			}
+		}
+	}
+
+	return r
+}
+
+func (r attributeResolver) value(n *node.Node, name string) (string, bool) {
+	if v, ok := n.GetAttributeValue(name); ok {
+		return v, true
+	}
+
+	if n.Type == component.TextTagName {
+		if v, ok := r.text[name]; ok {
+			return v, true
+		}
+	}
+
+	if v, ok := r.all[name]; ok {
+		return v, true
+	}
+
+	if comp, ok := component.Lookup(n.Type); ok {
+		v, ok := comp.DefaultAttributes(&component.RenderContext{})[name]
+		return v, ok
+	}
+
+	return "", false
+}
@@ -0,0 +1,210 @@
+package mjml
+
+import "strings"
+
+// Alignment is the horizontal alignment of a component.
+type Alignment string
+
+const (
+	AlignLeft    Alignment = "left"
+	AlignCenter  Alignment = "center"
+	AlignRight   Alignment = "right"
+	AlignJustify Alignment = "justify"
+)
+
+// VerticalAlignment is the vertical alignment of a component.
+type VerticalAlignment string
+
+const (
+	AlignTop    VerticalAlignment = "top"
+	AlignMiddle VerticalAlignment = "middle"
+	AlignBottom VerticalAlignment = "bottom"
+)
+
+// TextDirection is the direction of a section or group.
+type TextDirection string
+
+const (
+	LeftToRight TextDirection = "ltr"
+	RightToLeft TextDirection = "rtl"
+)
+
+// Align sets the align attribute.
+func Align(align Alignment) Attribute {
+	return Attr("align", string(align))
+}
+
+// TextAlign sets the text-align attribute.
+func TextAlign(align Alignment) Attribute {
+	return Attr("text-align", string(align))
+}
+
+// VerticalAlign sets the vertical-align attribute.
+func VerticalAlign(align VerticalAlignment) Attribute {
+	return Attr("vertical-align", string(align))
+}
+
+// Direction sets the direction attribute.
+func Direction(direction TextDirection) Attribute {
+	return Attr("direction", string(direction))
+}
+
+// FullWidth makes a section or wrapper span the full width of the viewport.
+func FullWidth() Attribute {
+	return Attr("full-width", "full-width")
+}
+
+// CSSClass sets the css-class attribute, classes added to the rendered HTML.
+func CSSClass(classes ...string) Attribute {
+	return Attr("css-class", strings.Join(classes, " "))
+}
+
+// MJClass sets the mj-class attribute, applying the attributes of the classes defined with Class.
+func MJClass(classes ...string) Attribute {
+	return Attr("mj-class", strings.Join(classes, " "))
+}
+
+// Padding sets the padding attribute, values uses the CSS shorthand order, e.g. Padding("10px", "25px").
+func Padding(values ...string) Attribute {
+	return Attr("padding", strings.Join(values, " "))
+}
+
+// PaddingTop sets the padding-top attribute.
+func PaddingTop(value string) Attribute {
+	return Attr("padding-top", value)
+}
+
+// PaddingRight sets the padding-right attribute.
+func PaddingRight(value string) Attribute {
+	return Attr("padding-right", value)
+}
+
+// PaddingBottom sets the padding-bottom attribute.
+func PaddingBottom(value string) Attribute {
+	return Attr("padding-bottom", value)
+}
+
+// PaddingLeft sets the padding-left attribute.
+func PaddingLeft(value string) Attribute {
+	return Attr("padding-left", value)
+}
+
+// InnerPadding sets the inner-padding attribute.
+func InnerPadding(values ...string) Attribute {
+	return Attr("inner-padding", strings.Join(values, " "))
+}
+
+// Width sets the width attribute.
+func Width(value string) Attribute {
+	return Attr("width", value)
+}
+
+// Height sets the height attribute.
+func Height(value string) Attribute {
+	return Attr("height", value)
+}
+
+// Color sets the color attribute.
+func Color(color string) Attribute {
+	return Attr("color", color)
+}
+
+// BackgroundColor sets the background-color attribute.
+func BackgroundColor(color string) Attribute {
+	return Attr("background-color", color)
+}
+
+// ContainerBackgroundColor sets the container-background-color attribute.
+func ContainerBackgroundColor(color string) Attribute {
+	return Attr("container-background-color", color)
+}
+
+// BackgroundURL sets the background-url attribute.
+func BackgroundURL(url string) Attribute {
+	return Attr("background-url", url)
+}
+
+// Border sets the border attribute.
+func Border(value string) Attribute {
+	return Attr("border", value)
+}
+
+// BorderRadius sets the border-radius attribute.
+func BorderRadius(value string) Attribute {
+	return Attr("border-radius", value)
+}
+
+// FontFamily sets the font-family attribute.
+func FontFamily(family string) Attribute {
+	return Attr("font-family", family)
+}
+
+// FontSize sets the font-size attribute.
+func FontSize(size string) Attribute {
+	return Attr("font-size", size)
+}
+
+// FontStyle sets the font-style attribute.
+func FontStyle(style string) Attribute {
+	return Attr("font-style", style)
+}
+
+// FontWeight sets the font-weight attribute.
+func FontWeight(weight string) Attribute {
+	return Attr("font-weight", weight)
+}
+
+// LineHeight sets the line-height attribute.
+func LineHeight(value string) Attribute {
+	return Attr("line-height", value)
+}
+
+// LetterSpacing sets the letter-spacing attribute.
+func LetterSpacing(value string) Attribute {
+	return Attr("letter-spacing", value)
+}
+
+// TextDecoration sets the text-decoration attribute.
+func TextDecoration(value string) Attribute {
+	return Attr("text-decoration", value)
+}
+
+// TextTransform sets the text-transform attribute.
+func TextTransform(value string) Attribute {
+	return Attr("text-transform", value)
+}
+
+// Href sets the href attribute.
+func Href(url string) Attribute {
+	return Attr("href", url)
+}
+
+// Target sets the target attribute of a link.
+func Target(target string) Attribute {
+	return Attr("target", target)
+}
+
+// Rel sets the rel attribute of a link.
+func Rel(rel string) Attribute {
+	return Attr("rel", rel)
+}
+
+// Src sets the src attribute.
+func Src(url string) Attribute {
+	return Attr("src", url)
+}
+
+// Alt sets the alt attribute.
+func Alt(text string) Attribute {
+	return Attr("alt", text)
+}
+
+// Name sets the name attribute, e.g. the network of a social element.
+func Name(name string) Attribute {
+	return Attr("name", name)
+}
+
+// Mode sets the mode attribute of a hero or social element.
+func Mode(mode string) Attribute {
+	return Attr("mode", mode)
+}
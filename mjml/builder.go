@@ -0,0 +1,142 @@
+// Package mjml provides a typed builder for MJML documents.
+//
+//	doc := mjml.Document(
+//		mjml.Body(
+//			mjml.Section(
+//				mjml.Column(
+//					mjml.Text("Hello World", mjml.Color("#ff0000")),
+//					mjml.Button("Open", mjml.Href("https://example.com")),
+//				),
+//			),
+//		),
+//	)
+//
+//	html, err := doc.Render()
+//
+// The document is turned into a node.Node tree directly, without generating and parsing MJML markup.
+package mjml
+
+import (
+	"errors"
+
+	"github.com/julez-dev/mjmlgo"
+	"github.com/julez-dev/mjmlgo/component"
+	"github.com/julez-dev/mjmlgo/node"
+)
+
+// ErrUnknownAttribute is returned by Element.Node for attributes not supported by a component.
+var ErrUnknownAttribute = errors.New("attribute is not allowed")
+
+// globalAttributes are allowed on every component.
+var globalAttributes = map[string]struct{}{
+	"css-class": {},
+	"mj-class":  {},
+}
+
+// Child is an argument of a container element, either a nested *Element or an Attribute.
+type Child interface {
+	apply(e *Element)
+}
+
+// Element is an MJML element created by one of the component functions like Section or Text.
+// Elements are immutable once built and can be used multiple times, e.g. a shared footer.
+type Element struct {
+	tag      string
+	content  string
+	attrs    []Attribute
+	children []*Element
+}
+
+func (e *Element) apply(parent *Element) {
+	parent.children = append(parent.children, e)
+}
+
+// Attribute is an attribute of an element, created by Attr or one of the typed setters like Padding.
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+func (a Attribute) apply(e *Element) {
+	e.attrs = append(e.attrs, a)
+}
+
+// Attr returns an attribute with any name, for attributes without a typed setter.
+func Attr(name, value string) Attribute {
+	return Attribute{Name: name, Value: value}
+}
+
+func newElement(tag, content string, children []Child) *Element {
+	e := &Element{tag: tag, content: content}
+	for _, child := range children {
+		child.apply(e)
+	}
+
+	return e
+}
+
+func newLeaf(tag, content string, attrs []Attribute) *Element {
+	return &Element{tag: tag, content: content, attrs: attrs}
+}
+
+// Node builds a new node tree from e. The attributes of all components are validated against their
+// allowed attributes, all errors are returned joined as *component.ValidationError.
+func (e *Element) Node() (*node.Node, error) {
+	var errs []error
+	n := e.build(nil, &errs)
+
+	return n, errors.Join(errs...)
+}
+
+func (e *Element) build(parent *node.Node, errs *[]error) *node.Node {
+	n := &node.Node{
+		Type:    e.tag,
+		Content: e.content,
+		Parent:  parent,
+	}
+
+	for _, attr := range e.attrs {
+		n.SetAttribute(attr.Name, attr.Value)
+	}
+
+	if comp, ok := component.Lookup(e.tag); ok {
+		allowed := comp.AllowedAttributes()
+		for _, attr := range n.Attributes {
+			name := node.AttributeName(attr)
+			if _, ok := globalAttributes[name]; ok {
+				continue
+			}
+
+			var err error
+			if validate, ok := allowed[name]; ok {
+				err = validate(attr.Value)
+			} else {
+				err = ErrUnknownAttribute
+			}
+
+			if err != nil {
+				*errs = append(*errs, &component.ValidationError{
+					Tag:       e.tag,
+					Attribute: name,
+					Err:       err,
+				})
+			}
+		}
+	}
+
+	for _, child := range e.children {
+		n.Children = append(n.Children, child.build(n, errs))
+	}
+
+	return n
+}
+
+// Render builds the node tree of e and renders it to HTML. e must be created by Document.
+func (e *Element) Render(opts ...mjmlgo.Option) (string, error) {
+	n, err := e.Node()
+	if err != nil {
+		return "", err
+	}
+
+	return mjmlgo.RenderNode(n, opts...)
+}
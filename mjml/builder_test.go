@@ -0,0 +1,94 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julez-dev/mjmlgo"
+	"github.com/julez-dev/mjmlgo/component"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	footer := Section(Column(Text("Footer", FontSize("10px"))))
+
+	doc := Document(
+		Head(Title("Hello")),
+		Body(
+			Section(
+				BackgroundColor("#f0f0f0"),
+				Padding("10px", "25px"),
+				Column(
+					Text("<b>Hello</b> World", Color("#ff0000"), Align(AlignCenter)),
+					Image(Src("https://example.com/a.png"), Alt("A"), Width("100px")),
+					Button("Open", Href("https://example.com"), CSSClass("cta", "big")),
+				),
+			),
+			footer,
+			footer,
+		),
+	)
+
+	out, err := doc.Render()
+	require.NoError(t, err)
+
+	expected, err := mjmlgo.RenderMJML(strings.NewReader(`<mjml><mj-head><mj-title>Hello</mj-title></mj-head><mj-body>` +
+		`<mj-section background-color="#f0f0f0" padding="10px 25px"><mj-column>` +
+		`<mj-text color="#ff0000" align="center"><b>Hello</b> World</mj-text>` +
+		`<mj-image src="https://example.com/a.png" alt="A" width="100px" />` +
+		`<mj-button href="https://example.com" css-class="cta big">Open</mj-button>` +
+		`</mj-column></mj-section>` +
+		`<mj-section><mj-column><mj-text font-size="10px">Footer</mj-text></mj-column></mj-section>` +
+		`<mj-section><mj-column><mj-text font-size="10px">Footer</mj-text></mj-column></mj-section>` +
+		`</mj-body></mjml>`))
+	require.NoError(t, err)
+
+	require.Equal(t, expected, out)
+}
+
+func TestRenderClass(t *testing.T) {
+	t.Parallel()
+
+	out, err := Document(
+		Head(Attributes(Class("red", Color("#ff0000")))),
+		Body(Section(Column(Text("Hi", MJClass("red"))))),
+	).Render()
+	require.NoError(t, err)
+
+	require.Contains(t, out, `color:#ff0000;">Hi<`)
+}
+
+func TestNode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tree", func(t *testing.T) {
+		n, err := Document(Body(Section(Column(Text("Hi"))))).Node()
+		require.NoError(t, err)
+
+		require.Equal(t, "mjml", n.Type)
+		text := n.Children[0].Children[0].Children[0].Children[0]
+		require.Equal(t, "mj-text", text.Type)
+		require.Equal(t, "Hi", text.Content)
+		require.Equal(t, "mj-column", text.Parent.Type)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := Document(Body(Section(
+			Attr("href", "https://example.com"),
+			Column(Text("Hi", Color("not-a-color"), MJClass("a"))),
+		))).Node()
+		require.Error(t, err)
+
+		var validationErr *component.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.ErrorIs(t, err, ErrUnknownAttribute)
+		require.ErrorContains(t, err, "failed to validate field href in <mj-section>")
+		require.ErrorContains(t, err, "failed to validate field color in <mj-text>")
+		require.NotContains(t, err.Error(), "mj-class")
+
+		_, err = Document(Body(Section(Column(Text("Hi", Color("not-a-color")))))).Render()
+		require.ErrorIs(t, err, component.ErrValidation)
+	})
+}
@@ -0,0 +1,134 @@
+package mjml
+
+import "github.com/julez-dev/mjmlgo/component"
+
+// Document returns the root <mjml> element.
+func Document(children ...Child) *Element {
+	return newElement(component.MJMLTagName, "", children)
+}
+
+// Head returns an <mj-head> element.
+func Head(children ...Child) *Element {
+	return newElement(component.HeadTagName, "", children)
+}
+
+// Title returns an <mj-title> element.
+func Title(title string) *Element {
+	return newLeaf(component.TitleTagName, title, nil)
+}
+
+// Preview returns an <mj-preview> element, the text shown in the inbox after the subject.
+func Preview(text string) *Element {
+	return newLeaf(component.PreviewTagName, text, nil)
+}
+
+// Style returns an <mj-style> element. If inline is true the styles are inlined into the elements.
+func Style(css string, inline bool) *Element {
+	var attrs []Attribute
+	if inline {
+		attrs = append(attrs, Attr("inline", "inline"))
+	}
+
+	return newLeaf(component.StyleTagName, css, attrs)
+}
+
+// Font returns an <mj-font> element importing the font name from href.
+func Font(name, href string) *Element {
+	return newLeaf(component.FontTagName, "", []Attribute{Attr("name", name), Attr("href", href)})
+}
+
+// Breakpoint returns an <mj-breakpoint> element, width is the viewport width switching to the mobile layout.
+func Breakpoint(width string) *Element {
+	return newLeaf(component.BreakpointTagName, "", []Attribute{Width(width)})
+}
+
+// Attributes returns an <mj-attributes> element. Its children, e.g. All, Class or Text, set the default
+// attributes of the components.
+func Attributes(children ...Child) *Element {
+	return newElement(component.AttributesTagName, "", children)
+}
+
+// All returns an <mj-all> element, setting default attributes for all components.
+func All(attrs ...Attribute) *Element {
+	return newLeaf(component.AllTagName, "", attrs)
+}
+
+// Class returns an <mj-class> element, defining the attributes used by components with mj-class="name".
+func Class(name string, attrs ...Attribute) *Element {
+	return newLeaf(component.ClassTagName, "", append([]Attribute{Attr("name", name)}, attrs...))
+}
+
+// Body returns an <mj-body> element.
+func Body(children ...Child) *Element {
+	return newElement(component.BodyTagName, "", children)
+}
+
+// Raw returns an <mj-raw> element, html is written to the output unchanged.
+func Raw(html string) *Element {
+	return newLeaf(component.RawTagName, html, nil)
+}
+
+// Section returns an <mj-section> element.
+func Section(children ...Child) *Element {
+	return newElement(component.SectionTagName, "", children)
+}
+
+// Wrapper returns an <mj-wrapper> element.
+func Wrapper(children ...Child) *Element {
+	return newElement(component.WrapperTagName, "", children)
+}
+
+// Hero returns an <mj-hero> element.
+func Hero(children ...Child) *Element {
+	return newElement(component.HeroTagName, "", children)
+}
+
+// Group returns an <mj-group> element.
+func Group(children ...Child) *Element {
+	return newElement(component.GroupTagName, "", children)
+}
+
+// Column returns an <mj-column> element.
+func Column(children ...Child) *Element {
+	return newElement(component.ColumnTagName, "", children)
+}
+
+// Text returns an <mj-text> element, html is its content.
+func Text(html string, attrs ...Attribute) *Element {
+	return newLeaf(component.TextTagName, html, attrs)
+}
+
+// Button returns an <mj-button> element, html is its label.
+func Button(html string, attrs ...Attribute) *Element {
+	return newLeaf(component.ButtonTagName, html, attrs)
+}
+
+// Image returns an <mj-image> element.
+func Image(attrs ...Attribute) *Element {
+	return newLeaf(component.ImageTagName, "", attrs)
+}
+
+// Table returns an <mj-table> element, html are the rows of the table.
+func Table(html string, attrs ...Attribute) *Element {
+	return newLeaf(component.TableTagName, html, attrs)
+}
+
+// Divider returns an <mj-divider> element.
+func Divider(attrs ...Attribute) *Element {
+	return newLeaf(component.DividerTagName, "", attrs)
+}
+
+// Spacer returns an <mj-spacer> element.
+func Spacer(attrs ...Attribute) *Element {
+	return newLeaf(component.SpacerTagName, "", attrs)
+}
+
+// Social returns an <mj-social> element.
+func Social(children ...Child) *Element {
+	return newElement(component.SocialTagName, "", children)
+}
+
+// SocialElement returns an <mj-social-element> element, html is its label.
+func SocialElement(html string, attrs ...Attribute) *Element {
+	return newLeaf(component.SocialElementTagName, html, attrs)
+}
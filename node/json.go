@@ -0,0 +1,123 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+)
+
+// jsonNode is the MJML JSON representation of a node, as used by the upstream MJML parser.
+type jsonNode struct {
+	TagName    string          `json:"tagName"`
+	Attributes json.RawMessage `json:"attributes,omitempty"`
+	Children   []*Node         `json:"children,omitempty"`
+	Content    string          `json:"content,omitempty"`
+	Line       int             `json:"line,omitempty"`
+}
+
+// MarshalJSON encodes the node in the MJML JSON format:
+// {"tagName": "...", "attributes": {...}, "children": [...], "content": "..."}.
+// Attributes are written in the order of n.Attributes.
+func (n Node) MarshalJSON() ([]byte, error) {
+	var attrs bytes.Buffer
+	attrs.WriteByte('{')
+	for i, attr := range n.Attributes {
+		if i > 0 {
+			attrs.WriteByte(',')
+		}
+
+		key, err := json.Marshal(AttributeName(attr))
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		attrs.Write(key)
+		attrs.WriteByte(':')
+		attrs.Write(value)
+	}
+	attrs.WriteByte('}')
+
+	return json.Marshal(jsonNode{
+		TagName:    n.Type,
+		Attributes: attrs.Bytes(),
+		Children:   n.Children,
+		Content:    n.Content,
+		Line:       n.Line,
+	})
+}
+
+// UnmarshalJSON decodes a node in the MJML JSON format. The order of the attributes is preserved
+// and the Parent of all children is set to n.
+func (n *Node) UnmarshalJSON(data []byte) error {
+	var v jsonNode
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if v.TagName == "" {
+		return fmt.Errorf("node: missing tagName")
+	}
+
+	attrs, err := unmarshalAttributes(v.Attributes)
+	if err != nil {
+		return err
+	}
+
+	*n = Node{
+		Type:       v.TagName,
+		Attributes: attrs,
+		Content:    v.Content,
+		Children:   v.Children,
+		Line:       v.Line,
+	}
+
+	for _, child := range n.Children {
+		if child == nil {
+			return fmt.Errorf("node: null child in <%s>", n.Type)
+		}
+		child.Parent = n
+	}
+
+	return nil
+}
+
+// unmarshalAttributes decodes a JSON object into attributes, keeping the order of the keys.
+// Non-string values like numbers are kept in their JSON representation.
+func unmarshalAttributes(data json.RawMessage) ([]xml.Attr, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, fmt.Errorf("node: attributes must be an object")
+	}
+
+	var attrs []xml.Attr
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := t.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		value := string(raw)
+		if err := json.Unmarshal(raw, &value); err != nil {
+			value = string(raw)
+		}
+
+		attrs = append(attrs, NewAttribute(key, value))
+	}
+
+	return attrs, nil
+}
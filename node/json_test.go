@@ -0,0 +1,68 @@
+package node
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("marshal", func(t *testing.T) {
+		root := &Node{
+			Type: "mj-section",
+			Attributes: []xml.Attr{
+				{Name: xml.Name{Local: "padding"}, Value: "0"},
+				{Name: xml.Name{Local: "background-color"}, Value: "#fff"},
+			},
+			Children: []*Node{
+				{Type: "mj-text", Content: "<b>Hi</b>", Line: 3},
+			},
+		}
+
+		b, err := json.Marshal(root)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"tagName": "mj-section",
+			"attributes": {"padding": "0", "background-color": "#fff"},
+			"children": [{"tagName": "mj-text", "attributes": {}, "content": "<b>Hi</b>", "line": 3}]
+		}`, string(b))
+		require.Contains(t, string(b), `{"padding":"0","background-color":"#fff"}`)
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		const input = `{
+			"tagName": "mj-column",
+			"attributes": {"width": "50%", "padding": 10, "css-class": "a", "v:fill": "true"},
+			"children": [
+				{"tagName": "mj-text", "content": "Hello"},
+				{"tagName": "mj-image", "attributes": {"src": "a.png"}}
+			]
+		}`
+
+		var n Node
+		require.NoError(t, json.Unmarshal([]byte(input), &n))
+
+		require.Equal(t, "mj-column", n.Type)
+		require.Equal(t, []xml.Attr{
+			{Name: xml.Name{Local: "width"}, Value: "50%"},
+			{Name: xml.Name{Local: "padding"}, Value: "10"},
+			{Name: xml.Name{Local: "css-class"}, Value: "a"},
+			{Name: xml.Name{Space: "v", Local: "fill"}, Value: "true"},
+		}, n.Attributes)
+		require.Len(t, n.Children, 2)
+		require.Equal(t, "Hello", n.Children[0].Content)
+		require.Same(t, &n, n.Children[1].Parent)
+		require.Equal(t, "a.png", n.Children[1].GetAttributeValueDefault("src"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var n Node
+		require.Error(t, json.Unmarshal([]byte(`{"attributes": {}}`), &n))
+		require.Error(t, json.Unmarshal([]byte(`{"tagName": "mjml", "attributes": []}`), &n))
+		require.Error(t, json.Unmarshal([]byte(`{"tagName": "mjml", "children": [null]}`), &n))
+	})
+}
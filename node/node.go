@@ -1,28 +1,54 @@
 package node
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"strings"
+)
 
 type Node struct {
-	Type       string
+	Type string
+	// Attributes are kept in source order. A namespace prefix like xlink in xlink:href is stored in Name.Space,
+	// the methods of Node address attributes by their qualified name, see AttributeName.
 	Attributes []xml.Attr `json:"-"`
 	Content    string
 	Children   []*Node
 	Parent     *Node `json:"-"`
+
+	// Line and Column are the 1-based position of the start tag in the source, 0 if unknown.
+	Line   int
+	Column int
+}
+
+// AttributeName returns the qualified name of attr as written in the source, e.g. xlink:href.
+func AttributeName(attr xml.Attr) string {
+	if attr.Name.Space == "" {
+		return attr.Name.Local
+	}
+	return attr.Name.Space + ":" + attr.Name.Local
+}
+
+// NewAttribute returns an attribute with the qualified name name. A namespace prefix is split off into Name.Space
+// the same way the parser does it.
+func NewAttribute(name, value string) xml.Attr {
+	if prefix, local, ok := strings.Cut(name, ":"); ok && prefix != "" && local != "" {
+		return xml.Attr{Name: xml.Name{Space: prefix, Local: local}, Value: value}
+	}
+	return xml.Attr{Name: xml.Name{Local: name}, Value: value}
 }
 
 func (n *Node) SetAttribute(name, value string) {
 	for i, attr := range n.Attributes {
-		if attr.Name.Local == name {
+		if AttributeName(attr) == name {
 			n.Attributes[i].Value = value
 			return
 		}
 	}
-	n.Attributes = append(n.Attributes, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+	n.Attributes = append(n.Attributes, NewAttribute(name, value))
 }
 
 func (n *Node) GetAttributeValue(name string) (string, bool) {
 	for _, attr := range n.Attributes {
-		if attr.Name.Local == name {
+		if AttributeName(attr) == name {
 			return attr.Value, true
 		}
 	}
@@ -31,7 +57,7 @@ func (n *Node) GetAttributeValue(name string) (string, bool) {
 
 func (n *Node) GetAttributeValueDefault(name string) string {
 	for _, attr := range n.Attributes {
-		if attr.Name.Local == name {
+		if AttributeName(attr) == name {
 			return attr.Value
 		}
 	}
@@ -0,0 +1,242 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidSelector = errors.New("node: invalid selector")
+
+// selector is one compound selector of a selector chain, e.g. mj-section.hero[mj-class~=dark].
+type selector struct {
+	tag     string // empty matches every tag
+	classes []string
+	attrs   []attributeSelector
+	// child is true if the previous selector must match the parent (">"), otherwise any ancestor.
+	child bool
+}
+
+type attributeSelector struct {
+	name  string
+	op    string // "", "=" or "~="
+	value string
+}
+
+// Select returns all descendants of n matching the CSS-like selector, in document order.
+//
+// Supported are tag names and "*", ".name" matching a class of css-class, attribute selectors
+// ([mj-class~=name], [href=value], [padding], [xlink:href]) and the descendant (" ") and child (">") combinators,
+// e.g. "mj-section.hero > mj-column mj-text[mj-class~=title]".
+func (n *Node) Select(query string) ([]*Node, error) {
+	chain, err := parseSelector(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*Node
+	for _, child := range n.Children {
+		child.Walk(func(n *Node) bool {
+			if matchChain(n, chain) {
+				found = append(found, n)
+			}
+			return true
+		})
+	}
+
+	return found, nil
+}
+
+func matchChain(n *Node, chain []selector) bool {
+	last := chain[len(chain)-1]
+	if !last.match(n) {
+		return false
+	}
+
+	if len(chain) == 1 {
+		return true
+	}
+
+	rest := chain[:len(chain)-1]
+	if last.child {
+		return n.Parent != nil && matchChain(n.Parent, rest)
+	}
+
+	for p := n.Parent; p != nil; p = p.Parent {
+		if matchChain(p, rest) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s selector) match(n *Node) bool {
+	if s.tag != "" && s.tag != n.Type {
+		return false
+	}
+
+	if len(s.classes) > 0 {
+		classes := strings.Fields(n.GetAttributeValueDefault("css-class"))
+		for _, class := range s.classes {
+			if !slices.Contains(classes, class) {
+				return false
+			}
+		}
+	}
+
+	for _, attr := range s.attrs {
+		value, ok := n.GetAttributeValue(attr.name)
+		if !ok {
+			return false
+		}
+
+		switch attr.op {
+		case "=":
+			if value != attr.value {
+				return false
+			}
+		case "~=":
+			if !slices.Contains(strings.Fields(value), attr.value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func parseSelector(query string) ([]selector, error) {
+	var (
+		chain []selector
+		child bool
+		rest  = strings.TrimSpace(query)
+	)
+
+	for rest != "" {
+		if rest[0] == '>' {
+			if len(chain) == 0 || child {
+				return nil, fmt.Errorf("%w: unexpected '>' in %q", ErrInvalidSelector, query)
+			}
+
+			child = true
+			rest = strings.TrimSpace(rest[1:])
+			continue
+		}
+
+		s, r, err := parseCompound(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w in %q", ErrInvalidSelector, err, query)
+		}
+
+		s.child = child
+		chain = append(chain, s)
+		child = false
+		rest = strings.TrimSpace(r)
+	}
+
+	if len(chain) == 0 || child {
+		return nil, fmt.Errorf("%w: incomplete selector %q", ErrInvalidSelector, query)
+	}
+
+	return chain, nil
+}
+
+// parseCompound parses one compound selector at the start of s and returns the remaining input.
+func parseCompound(s string) (selector, string, error) {
+	var sel selector
+
+	if s[0] == '*' {
+		s = s[1:]
+	} else {
+		sel.tag, s = cutIdent(s)
+	}
+
+	for s != "" {
+		switch s[0] {
+		case '.':
+			var class string
+			class, s = cutIdent(s[1:])
+			if class == "" {
+				return sel, "", errors.New("missing class name")
+			}
+			sel.classes = append(sel.classes, class)
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return sel, "", errors.New("missing ']'")
+			}
+
+			attr, err := parseAttributeSelector(s[1:end])
+			if err != nil {
+				return sel, "", err
+			}
+			sel.attrs = append(sel.attrs, attr)
+			s = s[end+1:]
+		case ' ', '\t', '\n', '>':
+			return sel, s, nil
+		default:
+			return sel, "", fmt.Errorf("unexpected %q", s[0])
+		}
+	}
+
+	return sel, s, nil
+}
+
+func parseAttributeSelector(s string) (attributeSelector, error) {
+	var attr attributeSelector
+
+	name, rest := cutQualifiedName(strings.TrimSpace(s))
+	if name == "" {
+		return attr, errors.New("missing attribute name")
+	}
+	attr.name = name
+
+	rest = strings.TrimSpace(rest)
+	switch {
+	case rest == "":
+		return attr, nil
+	case strings.HasPrefix(rest, "~="):
+		attr.op, rest = "~=", rest[2:]
+	case strings.HasPrefix(rest, "="):
+		attr.op, rest = "=", rest[1:]
+	default:
+		return attr, fmt.Errorf("unexpected %q in attribute selector", rest)
+	}
+
+	attr.value = strings.TrimSpace(rest)
+	if len(attr.value) >= 2 && (attr.value[0] == '"' || attr.value[0] == '\'') && attr.value[len(attr.value)-1] == attr.value[0] {
+		attr.value = attr.value[1 : len(attr.value)-1]
+	}
+
+	return attr, nil
+}
+
+// cutIdent returns the identifier (tag, class or attribute name) at the start of s and the remaining input.
+func cutIdent(s string) (string, string) {
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !(r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if end < 0 {
+		return s, ""
+	}
+
+	return s[:end], s[end:]
+}
+
+// cutQualifiedName returns the attribute name at the start of s, optionally with a namespace prefix like xlink:href,
+// and the remaining input.
+func cutQualifiedName(s string) (string, string) {
+	prefix, rest := cutIdent(s)
+	if prefix == "" || !strings.HasPrefix(rest, ":") {
+		return prefix, rest
+	}
+
+	local, after := cutIdent(rest[1:])
+	if local == "" {
+		return prefix, rest
+	}
+
+	return prefix + ":" + local, after
+}
@@ -0,0 +1,63 @@
+package node
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	root := testTree()
+
+	tests := []struct {
+		selector string
+		expected []string
+	}{
+		{"mj-text", []string{"mj-text:Title", "mj-text:Footer"}},
+		{".footer mj-text", []string{"mj-text:Footer"}},
+		{"mj-section.hero.dark > mj-column > *", []string{"mj-text:Title", "mj-image:"}},
+		{"mj-section > mj-text", nil},
+		{"mj-body mj-text[mj-class~=big]", []string{"mj-text:Title"}},
+		{"[src='a.png']", []string{"mj-image:"}},
+		{"[src]", []string{"mj-image:"}},
+		{"mj-text[mj-class=title]", nil},
+		{"mjml", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			found, err := root.Select(tt.selector)
+			require.NoError(t, err)
+
+			var got []string
+			for _, n := range found {
+				got = append(got, n.Type+":"+n.Content)
+			}
+			require.Equal(t, tt.expected, got)
+		})
+	}
+
+	t.Run("qualified-attribute-name", func(t *testing.T) {
+		root := &Node{Type: "mj-body"}
+		section := &Node{Type: "mj-section", Attributes: []xml.Attr{NewAttribute("xlink:href", "b.svg")}}
+		root.AppendChild(section)
+
+		for _, selector := range []string{"[xlink:href]", "mj-section[xlink:href='b.svg']"} {
+			found, err := root.Select(selector)
+			require.NoError(t, err)
+			require.Equal(t, []*Node{section}, found, selector)
+		}
+
+		found, err := root.Select("[href]")
+		require.NoError(t, err)
+		require.Empty(t, found)
+	})
+
+	for _, selector := range []string{"", "> mj-text", "mj-text >", "mj-text,mj-image", "[src", ".", "mj-text > > mj-image", "[xlink:]"} {
+		_, err := root.Select(selector)
+		require.ErrorIs(t, err, ErrInvalidSelector, selector)
+	}
+}
@@ -0,0 +1,161 @@
+package node
+
+import (
+	"encoding/xml"
+	"fmt"
+	"slices"
+)
+
+// Walk calls fn for n and all its descendants in document order.
+// The children of a node are skipped when fn returns false for it.
+func (n *Node) Walk(fn func(n *Node) bool) {
+	if !fn(n) {
+		return
+	}
+
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+// Find returns n and all its descendants with the tag name tag, in document order.
+func (n *Node) Find(tag string) []*Node {
+	var found []*Node
+	n.Walk(func(n *Node) bool {
+		if n.Type == tag {
+			found = append(found, n)
+		}
+		return true
+	})
+
+	return found
+}
+
+// AppendChild adds child as the last child of n. If child is already part of a tree, it is removed from there first.
+func (n *Node) AppendChild(child *Node) {
+	n.InsertChild(len(n.Children), child)
+}
+
+// InsertChild inserts child at index i of the children of n.
+// If child is already part of a tree, it is removed from there first.
+// It panics if child is n or one of its ancestors.
+func (n *Node) InsertChild(i int, child *Node) {
+	n.mustNotBeDescendantOf(child)
+
+	if child.Parent == n {
+		// removing the child from n shifts the following children
+		if idx := slices.Index(n.Children, child); idx >= 0 && idx < i {
+			i--
+		}
+	}
+
+	child.Remove()
+	child.Parent = n
+	n.Children = slices.Insert(n.Children, i, child)
+}
+
+// RemoveChild removes child from the children of n. It returns false if child is not a child of n.
+func (n *Node) RemoveChild(child *Node) bool {
+	idx := slices.Index(n.Children, child)
+	if idx < 0 {
+		return false
+	}
+
+	n.Children = slices.Delete(n.Children, idx, idx+1)
+	child.Parent = nil
+
+	return true
+}
+
+// ReplaceChild replaces old with replacement in the children of n. It returns false if old is not a child of n.
+// If replacement is already part of a tree, it is removed from there first.
+// It panics if replacement is n or one of its ancestors.
+func (n *Node) ReplaceChild(old, replacement *Node) bool {
+	if old == replacement {
+		return slices.Contains(n.Children, old)
+	}
+
+	n.mustNotBeDescendantOf(replacement)
+
+	if !slices.Contains(n.Children, old) {
+		return false
+	}
+
+	replacement.Remove()
+	idx := slices.Index(n.Children, old)
+	n.Children[idx] = replacement
+	replacement.Parent = n
+	old.Parent = nil
+
+	return true
+}
+
+// mustNotBeDescendantOf panics if n is ancestor or a descendant of it, adding ancestor to the children of n
+// would create a cycle.
+func (n *Node) mustNotBeDescendantOf(ancestor *Node) {
+	for p := n; p != nil; p = p.Parent {
+		if p == ancestor {
+			panic(fmt.Sprintf("node: can not add <%s> to itself or one of its descendants", ancestor.Type))
+		}
+	}
+}
+
+// Remove detaches n from its parent.
+func (n *Node) Remove() {
+	if n.Parent != nil {
+		n.Parent.RemoveChild(n)
+	}
+}
+
+// RemoveAttribute removes the attribute with the qualified name name from n.
+func (n *Node) RemoveAttribute(name string) {
+	n.Attributes = slices.DeleteFunc(n.Attributes, func(attr xml.Attr) bool {
+		return AttributeName(attr) == name
+	})
+}
+
+// Clone returns a deep copy of n. The copy has no parent.
+func (n *Node) Clone() *Node {
+	clone := &Node{
+		Type:       n.Type,
+		Attributes: slices.Clone(n.Attributes),
+		Content:    n.Content,
+		Line:       n.Line,
+		Column:     n.Column,
+	}
+
+	for _, child := range n.Children {
+		c := child.Clone()
+		c.Parent = clone
+		clone.Children = append(clone.Children, c)
+	}
+
+	return clone
+}
+
+// Equal reports whether n and other describe the same tree: the same tag names, content and children,
+// and the same attribute values regardless of their order. Parents and source positions are ignored.
+func (n *Node) Equal(other *Node) bool {
+	if n == nil || other == nil {
+		return n == other
+	}
+
+	if n.Type != other.Type || n.Content != other.Content ||
+		len(n.Attributes) != len(other.Attributes) || len(n.Children) != len(other.Children) {
+		return false
+	}
+
+	for _, attr := range n.Attributes {
+		if v, ok := other.GetAttributeValue(AttributeName(attr)); !ok || v != attr.Value {
+			return false
+		}
+	}
+
+	for i, child := range n.Children {
+		if !child.Equal(other.Children[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,182 @@
+package node
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testTree builds mjml > mj-body > (mj-section > mj-column > (mj-text, mj-image), mj-section.footer > mj-column > mj-text).
+func testTree() *Node {
+	root := &Node{Type: "mjml"}
+	body := &Node{Type: "mj-body"}
+	root.AppendChild(body)
+
+	section := &Node{Type: "mj-section", Attributes: []xml.Attr{{Name: xml.Name{Local: "css-class"}, Value: "hero dark"}}}
+	body.AppendChild(section)
+	column := &Node{Type: "mj-column"}
+	section.AppendChild(column)
+	column.AppendChild(&Node{Type: "mj-text", Content: "Title", Attributes: []xml.Attr{{Name: xml.Name{Local: "mj-class"}, Value: "title big"}}})
+	column.AppendChild(&Node{Type: "mj-image", Attributes: []xml.Attr{{Name: xml.Name{Local: "src"}, Value: "a.png"}}})
+
+	footer := &Node{Type: "mj-section", Attributes: []xml.Attr{{Name: xml.Name{Local: "css-class"}, Value: "footer"}}}
+	body.AppendChild(footer)
+	footerColumn := &Node{Type: "mj-column"}
+	footer.AppendChild(footerColumn)
+	footerColumn.AppendChild(&Node{Type: "mj-text", Content: "Footer"})
+
+	return root
+}
+
+func requireParents(t *testing.T, n *Node) {
+	t.Helper()
+
+	for _, child := range n.Children {
+		require.Same(t, n, child.Parent, "parent of <%s> in <%s>", child.Type, n.Type)
+		requireParents(t, child)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	var tags []string
+	testTree().Walk(func(n *Node) bool {
+		tags = append(tags, n.Type)
+		return n.GetAttributeValueDefault("css-class") != "footer"
+	})
+
+	require.Equal(t, []string{"mjml", "mj-body", "mj-section", "mj-column", "mj-text", "mj-image", "mj-section"}, tags)
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	texts := testTree().Find("mj-text")
+	require.Len(t, texts, 2)
+	require.Equal(t, "Title", texts[0].Content)
+	require.Equal(t, "Footer", texts[1].Content)
+
+	require.Empty(t, testTree().Find("mj-button"))
+}
+
+func TestMutation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("insert", func(t *testing.T) {
+		root := testTree()
+		body := root.Children[0]
+		footer := body.Children[1]
+
+		divider := &Node{Type: "mj-divider"}
+		body.InsertChild(0, divider)
+		require.Equal(t, []*Node{divider, body.Children[1], footer}, body.Children)
+
+		// moving a node within the same parent
+		body.InsertChild(3, divider)
+		require.Same(t, divider, body.Children[2])
+		require.Len(t, body.Children, 3)
+
+		// moving a node to another parent
+		column := root.Find("mj-column")[0]
+		column.AppendChild(divider)
+		require.Len(t, body.Children, 2)
+		require.Same(t, divider, column.Children[2])
+
+		requireParents(t, root)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		root := testTree()
+		body := root.Children[0]
+		column := root.Find("mj-column")[0]
+
+		require.PanicsWithValue(t, "node: can not add <mj-body> to itself or one of its descendants", func() {
+			column.AppendChild(body)
+		})
+		require.Panics(t, func() { body.InsertChild(0, body) })
+		require.Panics(t, func() { column.ReplaceChild(column.Children[0], body) })
+
+		requireParents(t, root)
+		require.Len(t, body.Children, 2)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		root := testTree()
+		image := root.Find("mj-image")[0]
+		column := image.Parent
+
+		image.Remove()
+		require.Nil(t, image.Parent)
+		require.Len(t, column.Children, 1)
+		require.False(t, column.RemoveChild(image))
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		root := testTree()
+		body := root.Children[0]
+		hero, footer := body.Children[0], body.Children[1]
+
+		replacement := &Node{Type: "mj-wrapper"}
+		require.True(t, body.ReplaceChild(hero, replacement))
+		require.Nil(t, hero.Parent)
+		require.Equal(t, []*Node{replacement, footer}, body.Children)
+
+		require.False(t, body.ReplaceChild(hero, &Node{Type: "mj-section"}))
+
+		require.True(t, body.ReplaceChild(replacement, footer))
+		require.Equal(t, []*Node{footer}, body.Children)
+
+		requireParents(t, root)
+	})
+
+	t.Run("remove-attribute", func(t *testing.T) {
+		n := &Node{Type: "mj-text"}
+		n.SetAttribute("color", "red")
+		n.SetAttribute("align", "left")
+
+		n.RemoveAttribute("color")
+		n.RemoveAttribute("unknown")
+		require.Equal(t, []xml.Attr{{Name: xml.Name{Local: "align"}, Value: "left"}}, n.Attributes)
+	})
+
+	t.Run("namespaced-attribute", func(t *testing.T) {
+		n := &Node{Type: "mj-raw"}
+		n.SetAttribute("href", "a.html")
+		n.SetAttribute("xlink:href", "b.svg")
+
+		require.Equal(t, []xml.Attr{
+			{Name: xml.Name{Local: "href"}, Value: "a.html"},
+			{Name: xml.Name{Space: "xlink", Local: "href"}, Value: "b.svg"},
+		}, n.Attributes)
+		require.Equal(t, "a.html", n.GetAttributeValueDefault("href"))
+		require.Equal(t, "b.svg", n.GetAttributeValueDefault("xlink:href"))
+
+		n.RemoveAttribute("href")
+		require.Equal(t, "xlink:href", AttributeName(n.Attributes[0]))
+	})
+}
+
+func TestCloneEqual(t *testing.T) {
+	t.Parallel()
+
+	root := testTree()
+	clone := root.Clone()
+
+	require.True(t, root.Equal(clone))
+	require.Nil(t, clone.Parent)
+	requireParents(t, clone)
+
+	clone.Find("mj-image")[0].SetAttribute("src", "b.png")
+	require.False(t, root.Equal(clone))
+	require.Equal(t, "a.png", root.Find("mj-image")[0].GetAttributeValueDefault("src"))
+
+	a := &Node{Type: "mj-text", Attributes: []xml.Attr{{Name: xml.Name{Local: "a"}, Value: "1"}, {Name: xml.Name{Local: "b"}, Value: "2"}}}
+	b := &Node{Type: "mj-text", Attributes: []xml.Attr{{Name: xml.Name{Local: "b"}, Value: "2"}, {Name: xml.Name{Local: "a"}, Value: "1"}}, Line: 4}
+	require.True(t, a.Equal(b))
+
+	b.Content = "x"
+	require.False(t, a.Equal(b))
+	require.False(t, a.Equal(nil))
+}
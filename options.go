@@ -0,0 +1,153 @@
+package mjmlgo
+
+import (
+	"github.com/julez-dev/mjmlgo/component"
+	"github.com/julez-dev/mjmlgo/node"
+)
+
+// Option configures the behaviour of RenderMJML.
+type Option func(*renderOptions)
+
+type renderOptions struct {
+	validationLevel component.ValidationLevel
+
+	linkRewriter            component.LinkRewriter
+	linkRewriterSkipSchemes []string
+
+	headHooks       []component.RenderHook
+	beforeBodyHooks []component.RenderHook
+	afterBodyHooks  []component.RenderHook
+
+	sizeBudget       int
+	sizeBudgetStrict bool
+
+	safeMode *SafePolicy
+
+	dropComments bool
+
+	recovery bool
+
+	sourceMap bool
+
+	containerWidth string
+	head           *node.Node
+}
+
+func newRenderOptions(opts []Option) *renderOptions {
+	o := &renderOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// a partial document is rendered as far as possible, unless strict validation was requested explicitly
+	if o.recovery && o.validationLevel == "" {
+		o.validationLevel = component.ValidationSoft
+	}
+
+	return o
+}
+
+// WithValidationLevel sets how attribute validation errors are handled, defaults to component.ValidationStrict,
+// or component.ValidationSoft with WithRecovery.
+// With component.ValidationSoft the errors are reported in RenderStats.ValidationErrors.
+func WithValidationLevel(level component.ValidationLevel) Option {
+	return func(o *renderOptions) {
+		o.validationLevel = level
+	}
+}
+
+// WithLinkRewriter calls fn for every link generated by a component (mj-button, mj-image, mj-social-element)
+// and writes the returned href instead. Links using one of skipSchemes, e.g. "mailto" or "tel", are not rewritten.
+func WithLinkRewriter(fn component.LinkRewriter, skipSchemes ...string) Option {
+	return func(o *renderOptions) {
+		o.linkRewriter = fn
+		o.linkRewriterSkipSchemes = skipSchemes
+	}
+}
+
+// WithHeadHook adds a hook writing into <head>, after all styles generated by MJML.
+func WithHeadHook(hook component.RenderHook) Option {
+	return func(o *renderOptions) {
+		o.headHooks = append(o.headHooks, hook)
+	}
+}
+
+// WithBeforeBodyHook adds a hook writing inside the body wrapper, before the first section.
+// This can be used to inject e.g. an open-tracking pixel.
+func WithBeforeBodyHook(hook component.RenderHook) Option {
+	return func(o *renderOptions) {
+		o.beforeBodyHooks = append(o.beforeBodyHooks, hook)
+	}
+}
+
+// WithAfterBodyHook adds a hook writing inside the body wrapper, after the last section.
+// This can be used to inject e.g. an unsubscribe footer.
+func WithAfterBodyHook(hook component.RenderHook) Option {
+	return func(o *renderOptions) {
+		o.afterBodyHooks = append(o.afterBodyHooks, hook)
+	}
+}
+
+// WithSizeBudget sets a limit in bytes for the rendered HTML, e.g. GmailClipSize.
+// If strict is true, rendering fails with ErrSizeBudgetExceeded when the limit is exceeded,
+// otherwise only RenderStats.BudgetExceeded is set.
+func WithSizeBudget(limit int, strict bool) Option {
+	return func(o *renderOptions) {
+		o.sizeBudget = limit
+		o.sizeBudgetStrict = strict
+	}
+}
+
+// WithSafeMode sanitizes the document before rendering, for MJML written by untrusted users.
+// It limits the input size and nesting depth, removes <mj-raw> and removes scripts, event handler attributes and URLs
+// with schemes not allowed by policy from attributes and the content of components like mj-text.
+// Everything removed is reported in RenderStats.Removed.
+func WithSafeMode(policy SafePolicy) Option {
+	return func(o *renderOptions) {
+		o.safeMode = &policy
+	}
+}
+
+// WithKeepComments sets whether comments are kept, defaults to true like upstream MJML.
+// Comments are rendered in place, e.g. for ESP directives like <!-- BEGIN:loop -->.
+// Comments inside the content of mj-raw and ending tags like mj-text are always kept.
+func WithKeepComments(keep bool) Option {
+	return func(o *renderOptions) {
+		o.dropComments = !keep
+	}
+}
+
+// WithRecovery enables a tolerant parse mode for live previews of documents that are still being edited.
+// Instead of failing with ErrParsingFailed, unclosed elements are closed automatically and invalid markup is skipped,
+// so the partial document is still rendered. Each recovery is reported in RenderStats.Recovered.
+// Validation defaults to component.ValidationSoft in this mode, as a partial document is often invalid.
+func WithRecovery() Option {
+	return func(o *renderOptions) {
+		o.recovery = true
+	}
+}
+
+// WithSourceMap adds a component.SourceMapAttribute like data-mj-node="12:5" with the line and column of the MJML
+// element to the root element of each rendered component, so a preview can map clicked elements back to the source.
+// It is meant for previews only, the attributes are not added without this option.
+func WithSourceMap() Option {
+	return func(o *renderOptions) {
+		o.sourceMap = true
+	}
+}
+
+// WithContainerWidth sets the width available to a fragment rendered with RenderFragment, e.g. "400px".
+// It defaults to the default mj-body width of 600px.
+func WithContainerWidth(width string) Option {
+	return func(o *renderOptions) {
+		o.containerWidth = width
+	}
+}
+
+// WithHead sets the <mj-head> applied to a fragment rendered with RenderFragment, e.g. parsed with Parse.
+// Its mj-attributes, mj-style, mj-font and mj-breakpoint elements are applied like in a document.
+func WithHead(head *node.Node) Option {
+	return func(o *renderOptions) {
+		o.head = head
+	}
+}
@@ -1,144 +1,226 @@
 package mjmlgo
 
 import (
-	"bytes"
-	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
-	"regexp"
 	"slices"
-	"strconv"
 	"strings"
 
 	"github.com/julez-dev/mjmlgo/component"
 	"github.com/julez-dev/mjmlgo/node"
 )
 
-// rawContentPlaceholderFormat defines the structure for the placeholder comment.
-const rawContentPlaceholderFormat = "RAW_PLACEHOLDER_%d"
-
-// rawTagRegex is used to find <mj-raw> blocks and capture their inner content.
-// The (?s) flag allows '.' to match newline characters.
-var rawTagRegex = regexp.MustCompile(`(?s)<mj-raw>(.*?)</mj-raw>`)
-
 var (
 	ErrParsingFailed = errors.New("parsing MJML structure failed")
+	// ErrInputTooLarge is returned in safe mode when the input exceeds SafePolicy.MaxInputSize.
+	ErrInputTooLarge = errors.New("mjml: input too large")
+	// ErrNestingTooDeep is returned in safe mode when elements are nested deeper than SafePolicy.MaxDepth.
+	ErrNestingTooDeep = errors.New("mjml: elements nested too deep")
 )
 
-func parse(input io.Reader) (*node.Node, error) {
-	fullBytes, err := io.ReadAll(input)
+// Parse parses MJML markup into a node tree without rendering it.
+// Only options affecting the parser, like WithSafeMode, are applied.
+//
+// With WithRecovery the partial tree is returned together with an error joining a *SyntaxError for each recovery,
+// the error is nil if nothing had to be recovered.
+func Parse(input io.Reader, opts ...Option) (*node.Node, error) {
+	root, recovered, err := parse(input, newRenderOptions(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	mjmlString := string(fullBytes)
-	var rawContents []string
-	processedMJML := rawTagRegex.ReplaceAllStringFunc(mjmlString, func(match string) string {
-		// Extract the inner content from the full match.
-		innerContent := rawTagRegex.FindStringSubmatch(match)[1]
-		// Store the raw inner content.
-		rawContents = append(rawContents, innerContent)
-		// Return a placeholder comment. The index will correspond to the slice.
-		placeholder := fmt.Sprintf(rawContentPlaceholderFormat, len(rawContents)-1)
-		return fmt.Sprintf("<!--%s-->", placeholder)
-	})
-
-	dec := xml.NewDecoder(strings.NewReader(processedMJML))
-	dec.Strict = false
+	if len(recovered) > 0 {
+		errs := make([]error, 0, len(recovered))
+		for _, syntaxErr := range recovered {
+			errs = append(errs, fmt.Errorf("%w: %w", ErrParsingFailed, syntaxErr))
+		}
+		return root, errors.Join(errs...)
+	}
+
+	return root, nil
+}
+
+// parse parses input into a node tree. In recovery mode, the syntax errors that were recovered from are returned
+// together with the partial tree.
+func parse(input io.Reader, options *renderOptions) (*node.Node, []*SyntaxError, error) {
+	safe := options.safeMode
+	if safe != nil {
+		input = io.LimitReader(input, int64(safe.maxInputSize())+1)
+	}
+
+	fullBytes, err := io.ReadAll(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if safe != nil && len(fullBytes) > safe.maxInputSize() {
+		return nil, nil, fmt.Errorf("%w: %w: more than %d bytes", ErrParsingFailed, ErrInputTooLarge, safe.maxInputSize())
+	}
+
+	z := newTokenizer(string(fullBytes))
+	z.recovery = options.recovery
 
 	var (
 		stack []*node.Node
 		root  *node.Node
+		// fileStart are <mj-raw position="file-start"> elements before the root element
+		fileStart []*node.Node
+		// recovered are the syntax errors skipped in recovery mode
+		recovered []*SyntaxError
 	)
 
 	for {
-		token, err := dec.Token()
+		t, err := z.next()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				break
 			}
 
-			return nil, fmt.Errorf("%w: %w", ErrParsingFailed, err)
+			var syntaxErr *SyntaxError
+			if options.recovery && errors.As(err, &syntaxErr) {
+				recovered = append(recovered, syntaxErr)
+				z.skipInvalid()
+				continue
+			}
+
+			return nil, nil, fmt.Errorf("%w: %w", ErrParsingFailed, err)
 		}
 
-		switch t := token.(type) {
-		case xml.StartElement:
+		switch t.kind {
+		case startTagToken:
+			if safe != nil && len(stack) >= safe.maxDepth() {
+				return nil, nil, fmt.Errorf("%w: %w: <%s> on line %d exceeds %d levels", ErrParsingFailed, ErrNestingTooDeep, t.name, t.line, safe.maxDepth())
+			}
+
 			node := &node.Node{
-				Type:       t.Name.Local,
-				Attributes: t.Attr,
+				Type:       t.name,
+				Attributes: t.attrs,
+				Line:       t.line,
+				Column:     t.column,
 			}
 
-			if _, has := mjmlEndTags[node.Type]; has {
-				s, err := streamInnerRawContent(dec, t)
+			// HTML void elements like <br> are often not closed by MJML authors
+			selfClosing := t.selfClosing || isVoidElement(node.Type)
+
+			if isRawContentTag(node.Type) && !selfClosing {
+				content, err := z.rawContent(node.Type)
 				if err != nil {
-					return nil, err
-				}
+					if !options.recovery {
+						return nil, nil, fmt.Errorf("%w: %w", ErrParsingFailed, err)
+					}
 
-				node.Content = s
-				// Attach to parent
-				if len(stack) > 0 {
-					parent := stack[len(stack)-1]
-					node.Parent = parent
-					parent.Children = append(parent.Children, node)
-				} else {
-					root = node
+					recovered = append(recovered, notClosedError(node))
+					content = z.unclosedContent()
 				}
 
-				// Do NOT push to stack, since streamInnerRawContent already consumes the end tag
-				continue
+				// Like upstream MJML, the content is trimmed but the whitespace inside is kept as written
+				node.Content = strings.TrimSpace(content)
 			}
 
 			if len(stack) > 0 {
 				parent := stack[len(stack)-1]
 				node.Parent = parent
 				parent.Children = append(parent.Children, node)
-			} else {
+			} else if node.Type != component.RawTagName {
 				root = node
+			} else if node.GetAttributeValueDefault("position") == "file-start" {
+				fileStart = append(fileStart, node)
 			}
 
-			stack = append(stack, node)
-		case xml.CharData:
+			// Raw content tags already consumed their end tag
+			if !selfClosing && !isRawContentTag(node.Type) {
+				stack = append(stack, node)
+			}
+		case textToken, cdataToken:
+			// The text is trimmed when the element is closed, so whitespace between text and child elements is kept
 			if len(stack) > 0 {
 				parent := stack[len(stack)-1]
-				content := string(bytes.TrimSpace(t))
-				parent.Content += content
+				parent.Content += t.data
+			}
+		case commentToken:
+			// Like upstream MJML, comments are kept as mj-raw elements, so they are rendered in place.
+			if len(stack) > 0 && !options.dropComments {
+				parent := stack[len(stack)-1]
+				comment := component.NewComment(t.data)
+				comment.Parent = parent
+				comment.Line, comment.Column = t.line, t.column
+				parent.Children = append(parent.Children, comment)
+			}
+		case endTagToken:
+			// Like encoding/xml in non-strict mode, unclosed elements are closed by the end tag of a parent.
+			i := len(stack) - 1
+			for i >= 0 && stack[i].Type != t.name {
+				i--
 			}
-		case xml.Comment:
-			// Check if this comment is one of our placeholders
-			comment := string(t)
-			if after, ok := strings.CutPrefix(comment, "RAW_PLACEHOLDER_"); ok {
-				// Parse the index from the placeholder string.
-				indexStr := strings.TrimSpace(after)
-				index, err := strconv.Atoi(indexStr)
-				if err != nil || index >= len(rawContents) {
-					// This shouldn't happen
-					continue
-				}
 
-				node := &node.Node{
-					Type:    "mj-raw",
-					Content: rawContents[index], // the original content
+			if i < 0 {
+				// end tags of void elements are valid, they were closed with their start tag
+				if options.recovery && !isVoidElement(t.name) {
+					recovered = append(recovered, &SyntaxError{Msg: fmt.Sprintf("unexpected </%s>", t.name), Line: t.line, Column: t.column})
 				}
+				break
+			}
 
-				if len(stack) > 0 {
-					parent := stack[len(stack)-1]
-					node.Parent = parent // Set parent node for raw content
-					parent.Children = append(parent.Children, node)
+			for _, closed := range stack[i:] {
+				closed.Content = trimXMLSpace(closed.Content)
+			}
+			if options.recovery {
+				for _, unclosed := range stack[i+1:] {
+					recovered = append(recovered, notClosedError(unclosed))
 				}
 			}
+			stack = stack[:i]
+		}
+	}
 
-		case xml.EndElement:
-			if len(stack) > 0 {
-				stack = stack[:len(stack)-1]
-			}
+	if len(stack) > 0 && options.recovery {
+		for _, unclosed := range stack {
+			unclosed.Content = trimXMLSpace(unclosed.Content)
+			recovered = append(recovered, notClosedError(unclosed))
 		}
+		stack = nil
+	}
+
+	if len(stack) > 0 {
+		unclosed := stack[len(stack)-1]
+		line, column := z.position(len(z.input))
+		return nil, nil, fmt.Errorf("%w: %w", ErrParsingFailed, &SyntaxError{
+			Msg:    fmt.Sprintf("unexpected EOF, <%s> on line %d is not closed", unclosed.Type, unclosed.Line),
+			Line:   line,
+			Column: column,
+		})
 	}
 
 	if root == nil {
-		return nil, fmt.Errorf("%w: no root element specified", ErrParsingFailed)
+		return nil, nil, fmt.Errorf("%w: no root element specified", ErrParsingFailed)
+	}
+
+	for _, raw := range slices.Backward(fileStart) {
+		root.InsertChild(0, raw)
 	}
 
+	// fragments rendered with RenderFragment have no head
+	if root.Type == "mjml" {
+		ensureHead(root)
+	}
+
+	return root, recovered, nil
+}
+
+// notClosedError returns the error recovered from when n is closed automatically in recovery mode.
+func notClosedError(n *node.Node) *SyntaxError {
+	return &SyntaxError{Msg: fmt.Sprintf("<%s> is not closed", n.Type), Line: n.Line, Column: n.Column}
+}
+
+// trimXMLSpace removes leading and trailing XML whitespace from s. Unlike strings.TrimSpace it keeps
+// non-breaking spaces, which are only present in text if they were written as an entity like &nbsp;.
+func trimXMLSpace(s string) string {
+	return strings.Trim(s, " \t\r\n")
+}
+
+// ensureHead adds an empty <mj-head> to root if it has none.
+func ensureHead(root *node.Node) {
 	if !slices.ContainsFunc(root.Children, func(e *node.Node) bool {
 		return e.Type == component.HeadTagName
 	}) {
@@ -147,8 +229,6 @@ func parse(input io.Reader) (*node.Node, error) {
 			Parent: root,
 		})
 	}
-
-	return root, nil
 }
 
 // mjmlEndTags is a map of MJML tags whose inner content should be
@@ -163,47 +243,36 @@ var mjmlEndTags = map[string]struct{}{
 	"mj-social-element":  struct{}{},
 }
 
-// streamInnerRawContent captures the inner content of an element as a raw string.
-// It starts after the initial start tag and stops before the final end tag.
-func streamInnerRawContent(decoder *xml.Decoder, startElement xml.StartElement) (string, error) {
-	var builder strings.Builder
-	depth := 1
-
-	for {
-		token, err := decoder.Token()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				return "", fmt.Errorf("unexpected EOF while streaming raw content for <%s>", startElement.Name.Local)
-			}
+// isRawContentTag reports whether the content of tag is kept as a raw string instead of being parsed into child nodes.
+func isRawContentTag(tag string) bool {
+	if tag == component.RawTagName {
+		return true
+	}
 
-			return "", err
-		}
+	_, ok := mjmlEndTags[tag]
+	return ok
+}
 
-		// Check for the final EndElement before writing, so we don't include it
-		if end, ok := token.(xml.EndElement); ok && end.Name.Local == startElement.Name.Local {
-			depth--
-			if depth == 0 {
-				return builder.String(), nil // We're done
-			}
-		}
+// htmlVoidElements are the HTML elements without content, see https://html.spec.whatwg.org/#void-elements.
+var htmlVoidElements = map[string]struct{}{
+	"area":   {},
+	"base":   {},
+	"br":     {},
+	"col":    {},
+	"embed":  {},
+	"hr":     {},
+	"img":    {},
+	"input":  {},
+	"link":   {},
+	"meta":   {},
+	"param":  {},
+	"source": {},
+	"track":  {},
+	"wbr":    {},
+}
 
-		// Re-serialize the token back to a string.
-		switch se := token.(type) {
-		case xml.StartElement:
-			if se.Name.Local == startElement.Name.Local {
-				depth++
-			}
-			builder.WriteString("<" + se.Name.Local)
-			for _, attr := range se.Attr {
-				builder.WriteString(fmt.Sprintf(` %s="%s"`, attr.Name.Local, attr.Value))
-			}
-			builder.WriteString(">")
-		case xml.EndElement:
-			builder.WriteString("</" + se.Name.Local + ">")
-		case xml.CharData:
-			builder.Write(se)
-		case xml.Comment:
-			builder.WriteString(fmt.Sprintf("<!--%s-->", string(se)))
-		}
-	}
+// isVoidElement reports whether tag is an HTML void element, which is closed even without "/>".
+func isVoidElement(tag string) bool {
+	_, ok := htmlVoidElements[tag]
+	return ok
 }
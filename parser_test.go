@@ -1,6 +1,7 @@
 package mjmlgo
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -18,7 +19,7 @@ func TestParse(t *testing.T) {
 		</mjml>
 		`
 
-		n, err := parse(strings.NewReader(input))
+		n, _, err := parse(strings.NewReader(input), newRenderOptions(nil))
 		require.NoError(t, err)
 
 		var rawContent string
@@ -38,7 +39,7 @@ func TestParse(t *testing.T) {
 	t.Run("mj-end-tags", func(t *testing.T) {
 		const input = `<mjml><mj-text><h1>Test</h1></mj-text></mjml>`
 
-		n, err := parse(strings.NewReader(input))
+		n, _, err := parse(strings.NewReader(input), newRenderOptions(nil))
 		require.NoError(t, err)
 
 		var rawContent string
@@ -50,4 +51,230 @@ func TestParse(t *testing.T) {
 
 		require.Equal(t, "<h1>Test</h1>", rawContent)
 	})
+	t.Run("positions", func(t *testing.T) {
+		const input = `<mjml>
+  <mj-body>
+    <mj-raw>
+      <p>raw</p>
+    </mj-raw>
+    <mj-section></mj-section>
+  </mj-body>
+</mjml>`
+
+		n, _, err := parse(strings.NewReader(input), newRenderOptions(nil))
+		require.NoError(t, err)
+
+		body := n.Children[1]
+		require.Equal(t, "mj-body", body.Type)
+		require.Equal(t, 2, body.Line)
+		require.Equal(t, 3, body.Column)
+
+		require.Equal(t, "mj-raw", body.Children[0].Type)
+		require.Equal(t, 3, body.Children[0].Line)
+		require.Equal(t, "mj-section", body.Children[1].Type)
+		require.Equal(t, 6, body.Children[1].Line)
+		require.Equal(t, 5, body.Children[1].Column)
+	})
+
+	t.Run("raw-content", func(t *testing.T) {
+		const input = `<mjml>
+  <mj-body>
+    <!-- <mj-raw>commented</mj-raw> -->
+    <!--RAW_PLACEHOLDER_0-->
+    <mj-raw position="file-start"><p class='a'>&nbsp;&amp;<br/></p><!-- </mj-raw> --></mj-raw>
+    <mj-raw><mj-raw>nested</mj-raw></mj-raw>
+    <mj-section>
+      <mj-column>
+        <mj-text align=left css-class><![CDATA[</mj-text>]]><img src="a.png?a=1&amp;b=2"/> &lt;b&gt;</mj-text>
+        <mj-text />
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+		n, _, err := parse(strings.NewReader(input), newRenderOptions([]Option{WithKeepComments(false)}))
+		require.NoError(t, err)
+
+		body := n.Children[1]
+		require.Len(t, body.Children, 3)
+
+		raw := body.Children[0]
+		require.Equal(t, "mj-raw", raw.Type)
+		require.Equal(t, "file-start", raw.GetAttributeValueDefault("position"))
+		require.Equal(t, `<p class='a'>&nbsp;&amp;<br/></p><!-- </mj-raw> -->`, raw.Content)
+		require.Equal(t, 5, raw.Line)
+
+		require.Equal(t, "<mj-raw>nested</mj-raw>", body.Children[1].Content)
+
+		texts := n.Find("mj-text")
+		require.Len(t, texts, 2)
+		require.Equal(t, `<![CDATA[</mj-text>]]><img src="a.png?a=1&amp;b=2"/> &lt;b&gt;`, texts[0].Content)
+		require.Equal(t, "left", texts[0].GetAttributeValueDefault("align"))
+		require.Equal(t, "true", texts[0].GetAttributeValueDefault("css-class"))
+		require.Empty(t, texts[1].Content)
+		require.Equal(t, 10, texts[1].Line)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		const input = `<mjml><mj-head><mj-title> Tom &amp; Jerry &#x2764; &nbsp;</mj-title><mj-style><![CDATA[ a > b { color: red; } ]]></mj-style></mj-head></mjml>`
+
+		n, _, err := parse(strings.NewReader(input), newRenderOptions(nil))
+		require.NoError(t, err)
+
+		head := n.Children[0]
+		require.Equal(t, "Tom & Jerry \u2764 \u00a0", head.Children[0].Content)
+		require.Equal(t, "a > b { color: red; }", head.Children[1].Content)
+	})
+
+	t.Run("whitespace", func(t *testing.T) {
+		const input = `<mjml>
+  <mj-head>
+    <mj-style>
+      .a { color: red; }
+      <!-- b -->
+      .b { color: blue; }
+    </mj-style>
+  </mj-head>
+  <mj-body>
+    <mj-raw>
+      <p>  raw  </p>
+    </mj-raw>
+  </mj-body>
+</mjml>`
+
+		n, _, err := parse(strings.NewReader(input), newRenderOptions([]Option{WithKeepComments(false)}))
+		require.NoError(t, err)
+
+		require.Equal(t, ".a { color: red; }\n      \n      .b { color: blue; }", n.Find("mj-style")[0].Content)
+		require.Equal(t, "<p>  raw  </p>", n.Find("mj-raw")[0].Content)
+		require.Empty(t, n.Find("mj-body")[0].Content)
+	})
+
+	t.Run("html", func(t *testing.T) {
+		const input = `<mjml>
+  <mj-body>
+    <mj-raw><p>Line<br>break <img src="a.png" alt=Logo hidden></p></mj-raw>
+    <br>
+    <mj-section full-width=full-width>
+      <mj-column>
+        <mj-text>Caf&eacute; &copy; 2024<hr></mj-text>
+        <mj-image src="a.png" alt="Caf&eacute; &unknown; &amp;" />
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+		n, _, err := parse(strings.NewReader(input), newRenderOptions(nil))
+		require.NoError(t, err)
+
+		body := n.Children[1]
+		require.Len(t, body.Children, 3)
+		require.Equal(t, `<p>Line<br>break <img src="a.png" alt=Logo hidden></p>`, body.Children[0].Content)
+		require.Equal(t, "br", body.Children[1].Type)
+		require.Empty(t, body.Children[1].Children)
+		require.Equal(t, "mj-section", body.Children[2].Type)
+		require.Equal(t, "full-width", body.Children[2].GetAttributeValueDefault("full-width"))
+
+		require.Equal(t, "Caf&eacute; &copy; 2024<hr>", n.Find("mj-text")[0].Content)
+		require.Equal(t, "Caf\u00e9 &unknown; &", n.Find("mj-image")[0].GetAttributeValueDefault("alt"))
+	})
+
+	t.Run("syntax-errors", func(t *testing.T) {
+		tests := []struct {
+			input  string
+			line   int
+			column int
+		}{
+			{"<mjml>\n  <mj-body>\n    <mj-section", 3, 16},
+			{"<mjml>\n  <mj-body>\n    <mj-text>open\n", 4, 1},
+			{"<mjml>\n  <mj-body>", 2, 12},
+			{"<mjml>\n  <mj-body attr=\"x>\n", 2, 17},
+			{"<mjml>\n  < mj-body>", 2, 3},
+		}
+
+		for _, tt := range tests {
+			_, _, err := parse(strings.NewReader(tt.input), newRenderOptions(nil))
+			require.ErrorIs(t, err, ErrParsingFailed, tt.input)
+
+			var syntaxErr *SyntaxError
+			require.ErrorAs(t, err, &syntaxErr, tt.input)
+			require.Equal(t, tt.line, syntaxErr.Line, tt.input)
+			require.Equal(t, tt.column, syntaxErr.Column, tt.input)
+		}
+	})
+
+	t.Run("recovery", func(t *testing.T) {
+		const input = `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text>Hello</mj-column>
+      </mj-section>
+      < mj-section>
+      <mj-section>
+        <mj-column>
+          <mj-image src="a.png"></mj-text>
+        </mj-column>
+  </mj-body>
+  <mj-sec`
+
+		_, err := Parse(strings.NewReader(input))
+		require.ErrorIs(t, err, ErrParsingFailed)
+
+		n, err := Parse(strings.NewReader(input), WithRecovery())
+		require.ErrorIs(t, err, ErrParsingFailed)
+		require.NotNil(t, n)
+
+		var syntaxErr *SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+
+		sections := n.Find("mj-section")
+		require.Len(t, sections, 2)
+		require.Equal(t, "Hello", n.Find("mj-text")[0].Content)
+		require.Len(t, n.Find("mj-image"), 1)
+		require.Same(t, sections[1], n.Find("mj-image")[0].Parent.Parent)
+
+		_, recovered, err := parse(strings.NewReader(input), newRenderOptions([]Option{WithRecovery()}))
+		require.NoError(t, err)
+
+		var got []string
+		for _, r := range recovered {
+			got = append(got, fmt.Sprintf("%d:%d: %s", r.Line, r.Column, r.Msg))
+		}
+		require.Equal(t, []string{
+			"5:9: <mj-text> is not closed",
+			"7:7: expected element name after <",
+			"10:33: unexpected </mj-text>",
+			"10:11: <mj-image> is not closed",
+			"8:7: <mj-section> is not closed",
+			"13:10: unexpected EOF in <mj-sec>",
+			"1:1: <mjml> is not closed",
+		}, got)
+	})
+
+	t.Run("comments", func(t *testing.T) {
+		const input = `<!-- before root -->
+<mjml>
+  <mj-body>
+    <!--   BEGIN:loop   -->
+    <mj-section>
+      <mj-column><!-- column --></mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+		n, _, err := parse(strings.NewReader(input), newRenderOptions(nil))
+		require.NoError(t, err)
+
+		body := n.Children[1]
+		require.Len(t, body.Children, 2)
+		require.Equal(t, "mj-raw", body.Children[0].Type)
+		require.Equal(t, "<!-- BEGIN:loop -->", body.Children[0].Content)
+		require.Equal(t, 4, body.Children[0].Line)
+		require.Equal(t, "<!-- column -->", n.Find("mj-column")[0].Children[0].Content)
+
+		n, _, err = parse(strings.NewReader(input), newRenderOptions([]Option{WithKeepComments(false)}))
+		require.NoError(t, err)
+		require.Empty(t, n.Find("mj-raw"))
+	})
 }
@@ -5,57 +5,252 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/ericchiang/css"
 	"github.com/julez-dev/mjmlgo/component"
+	"github.com/julez-dev/mjmlgo/node"
 	"golang.org/x/net/html"
 )
 
 var ErrUnknownStartingTag = errors.New("mjml: unknown starting tag")
 var duplicateConditionalComments = regexp.MustCompile(`<!\[endif\]-->\s*<!--\[if mso \| IE\]>`)
 
-func RenderMJML(input io.Reader) (string, error) {
-	node, err := parse(input)
+func RenderMJML(input io.Reader, opts ...Option) (string, error) {
+	out, _, err := RenderMJMLWithStats(input, opts...)
 	if err != nil {
 		return "", err
 	}
 
+	return out, nil
+}
+
+// RenderMJMLWithStats renders input like RenderMJML and additionally returns statistics about the output.
+// If a strict size budget is exceeded, the rendered HTML and stats are returned together with ErrSizeBudgetExceeded.
+func RenderMJMLWithStats(input io.Reader, opts ...Option) (string, *RenderStats, error) {
+	options := newRenderOptions(opts)
+
+	node, recovered, err := parse(input, options)
+	if err != nil {
+		return "", nil, err
+	}
+
+	out, stats, err := render(node, options)
+	if stats != nil {
+		stats.Recovered = recovered
+	}
+
+	return out, stats, err
+}
+
+// RenderNode renders a node tree, e.g. built with the mjml package or modified after Parse, to HTML
+// without going through the parser. Rendering adds the default attributes of each component to the tree.
+func RenderNode(root *node.Node, opts ...Option) (string, error) {
+	ensureHead(root)
+
+	out, _, err := render(root, newRenderOptions(opts))
+	if err != nil {
+		return "", err
+	}
+
+	return out, nil
+}
+
+// render renders a parsed <mjml> tree to HTML.
+func render(node *node.Node, options *renderOptions) (string, *RenderStats, error) {
 	if node.Type != "mjml" {
-		return "", fmt.Errorf("%w: %s", ErrUnknownStartingTag, node.Type)
+		return "", nil, fmt.Errorf("%w: %s", ErrUnknownStartingTag, node.Type)
+	}
+
+	var removed []Removal
+	if options.safeMode != nil {
+		var err error
+		if removed, err = sanitize(node, *options.safeMode); err != nil {
+			return "", nil, err
+		}
 	}
 
 	var buff strings.Builder
 	mjml := component.MJML{}
 
-	ctx := &component.RenderContext{
+	ctx := newRenderContext(options)
+	if err := component.InitComponent(ctx, mjml, node); err != nil {
+		return "", nil, err
+	}
+
+	if err := mjml.Render(ctx, &buff, node); err != nil {
+		return "", nil, err
+	}
+
+	var out strings.Builder
+	inlined, err := inlineCSS(ctx, strings.NewReader(buff.String()), &out)
+	if err != nil {
+		return "", nil, err
+	}
+
+	result := out.String()
+	result = duplicateConditionalComments.ReplaceAllString(result, "")
+	// file-start content is added after inlining, the HTML parser would move it into the body
+	if len(ctx.FileStart) > 0 {
+		result = strings.Join(ctx.FileStart, "\n") + "\n" + result
+	}
+
+	stats := &RenderStats{
+		Size:             len(result),
+		InlineStyleBytes: inlined,
+		Budget:           options.sizeBudget,
+		ValidationErrors: ctx.ValidationErrors,
+		Removed:          removed,
+	}
+	stats.Images, stats.Links = countElements(result)
+	for _, section := range ctx.SectionSizes {
+		stats.Sections = append(stats.Sections, SectionStats{
+			Type:     section.Type,
+			CSSClass: section.CSSClass,
+			Size:     section.Bytes,
+		})
+	}
+
+	if options.sizeBudget > 0 && stats.Size > options.sizeBudget {
+		stats.BudgetExceeded = true
+		if options.sizeBudgetStrict {
+			return result, stats, fmt.Errorf("%w: %d bytes, budget is %d bytes", ErrSizeBudgetExceeded, stats.Size, options.sizeBudget)
+		}
+	}
+
+	return result, stats, nil
+}
+
+func newRenderContext(options *renderOptions) *component.RenderContext {
+	return &component.RenderContext{
 		MJMLStylesheet: make(map[string][]string),
 		Fonts:          make(map[string]string),
+
+		ValidationLevel:         options.validationLevel,
+		LinkRewriter:            options.linkRewriter,
+		LinkRewriterSkipSchemes: options.linkRewriterSkipSchemes,
+		HeadHooks:               options.headHooks,
+		BeforeBodyHooks:         options.beforeBodyHooks,
+		AfterBodyHooks:          options.afterBodyHooks,
+		SourceMap:               options.sourceMap,
 	}
-	if err := component.InitComponent(ctx, mjml, node); err != nil {
-		return "", err
+}
+
+// Validate parses and renders input with component.ValidationSoft and returns all attribute validation errors.
+// The returned error is non-nil if the document could not be parsed or rendered at all.
+func Validate(input io.Reader, opts ...Option) ([]*component.ValidationError, error) {
+	opts = append(opts, WithValidationLevel(component.ValidationSoft))
+
+	_, stats, err := RenderMJMLWithStats(input, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := mjml.Render(ctx, &buff, node); err != nil {
-		return "", err
+	return stats.ValidationErrors, nil
+}
+
+// inlineCSS applies the inline stylesheets of ctx to the HTML read from r and writes the result to w.
+// It returns the number of bytes added to the document by inlining.
+func inlineCSS(ctx *component.RenderContext, r io.Reader, w io.Writer) (int, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
 	}
 
-	//spew.Dump(ctx.InlineStyles)
+	shielded, spans := shieldRawText(string(b))
+	htmlNode, err := html.Parse(strings.NewReader(shielded))
+	if err != nil {
+		return 0, err
+	}
+
+	added, err := applyInlineStyles(ctx, htmlNode)
+	if err != nil {
+		return 0, err
+	}
 
 	var out strings.Builder
-	if err := inlineCSS(ctx, strings.NewReader(buff.String()), &out); err != nil {
-		return "", err
+	if err := html.Render(&out, htmlNode); err != nil {
+		return 0, err
 	}
 
-	return duplicateConditionalComments.ReplaceAllString(out.String(), ""), nil
+	_, err = io.WriteString(w, restoreRawText(out.String(), spans))
+	return added, err
 }
 
-func inlineCSS(ctx *component.RenderContext, r io.Reader, w io.Writer) error {
-	htmlNode, err := html.Parse(r)
-	if err != nil {
-		return err
+var (
+	characterReference = regexp.MustCompile(`&(#[0-9]+|#[xX][0-9a-fA-F]+|[A-Za-z][A-Za-z0-9]*);`)
+	rawTextPlaceholder = regexp.MustCompile("\ue000([0-9]+)\ue001")
+)
+
+// shieldRawText replaces the CDATA sections of s and the character references in its text with placeholders.
+// The HTML parser would decode them and html.Render would write them back in a different form, e.g. &nbsp; as
+// a literal no-break space and a CDATA section as a comment. The replaced spans are returned for restoreRawText.
+func shieldRawText(s string) (string, []string) {
+	var spans []string
+
+	placeholder := func(span string) string {
+		spans = append(spans, span)
+		return "\ue000" + strconv.Itoa(len(spans)-1) + "\ue001"
 	}
 
+	// CDATA sections are cut out before tokenizing, the tokenizer ends them at the first > of markup they contain
+	var cdata strings.Builder
+	for {
+		start := strings.Index(s, "<![CDATA[")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], "]]>")
+		if end < 0 {
+			break
+		}
+		end += start + len("]]>")
+
+		cdata.WriteString(s[:start])
+		cdata.WriteString(placeholder(s[start:end]))
+		s = s[end:]
+	}
+	cdata.WriteString(s)
+
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(cdata.String()))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		raw := string(z.Raw())
+		if tt == html.TextToken {
+			raw = characterReference.ReplaceAllStringFunc(raw, placeholder)
+		}
+		b.WriteString(raw)
+	}
+
+	return b.String(), spans
+}
+
+// restoreRawText puts the spans replaced by shieldRawText back into s.
+func restoreRawText(s string, spans []string) string {
+	if len(spans) == 0 {
+		return s
+	}
+
+	return rawTextPlaceholder.ReplaceAllStringFunc(s, func(p string) string {
+		i, err := strconv.Atoi(rawTextPlaceholder.FindStringSubmatch(p)[1])
+		if err != nil || i >= len(spans) {
+			return p
+		}
+		return spans[i]
+	})
+}
+
+// applyInlineStyles adds the declarations of the inline stylesheets of ctx to the style attribute of the matching
+// descendants of htmlNode. It returns the number of bytes added to the document.
+func applyInlineStyles(ctx *component.RenderContext, htmlNode *html.Node) (int, error) {
+	var added int
+
 	for _, sheet := range ctx.InlineStyles {
 		for _, rule := range sheet.Rules {
 			sel, err := css.Parse(rule.Selectors)
@@ -77,7 +272,7 @@ func inlineCSS(ctx *component.RenderContext, r io.Reader, w io.Writer) error {
 
 				styles, err := parseStyleAttribute(styleAttr)
 				if err != nil {
-					return fmt.Errorf("failed to parse style attribute: %w", err)
+					return 0, fmt.Errorf("failed to parse style attribute: %w", err)
 				}
 
 				for _, dec := range rule.Declarations {
@@ -91,6 +286,7 @@ func inlineCSS(ctx *component.RenderContext, r io.Reader, w io.Writer) error {
 					styleText += strings.TrimSpace(fmt.Sprintf("%s:%s;", k, v))
 				}
 
+				added += len(styleText) - len(styleAttr.Val)
 				styleAttr.Val = styleText
 
 				if n.Data == "table" || n.Data == "td" || n.Data == "div" {
@@ -104,10 +300,10 @@ func inlineCSS(ctx *component.RenderContext, r io.Reader, w io.Writer) error {
 						}
 						if !alreadyHasWidth {
 							if strings.HasSuffix(v, "px") {
-								n.Attr = append(n.Attr, html.Attribute{Key: "width", Val: component.RemoveNonNumeric(v)})
-							} else {
-								n.Attr = append(n.Attr, html.Attribute{Key: "width", Val: v})
+								v = component.RemoveNonNumeric(v)
 							}
+							n.Attr = append(n.Attr, html.Attribute{Key: "width", Val: v})
+							added += attributeSize(n.Attr[len(n.Attr)-1])
 						}
 					}
 				}
@@ -122,6 +318,7 @@ func inlineCSS(ctx *component.RenderContext, r io.Reader, w io.Writer) error {
 					}
 					if !alreadyHasAlign {
 						n.Attr = append(n.Attr, html.Attribute{Key: "align", Val: v})
+						added += attributeSize(n.Attr[len(n.Attr)-1])
 					}
 				}
 
@@ -135,6 +332,7 @@ func inlineCSS(ctx *component.RenderContext, r io.Reader, w io.Writer) error {
 					}
 					if !alreadyHasAlign {
 						n.Attr = append(n.Attr, html.Attribute{Key: "valign", Val: v})
+						added += attributeSize(n.Attr[len(n.Attr)-1])
 					}
 				}
 
@@ -148,11 +346,13 @@ func inlineCSS(ctx *component.RenderContext, r io.Reader, w io.Writer) error {
 					}
 					if !alreadyHasBgColor {
 						n.Attr = append(n.Attr, html.Attribute{Key: "bgcolor", Val: styles["background-color"]})
+						added += attributeSize(n.Attr[len(n.Attr)-1])
 					}
 				}
 
 				if styleIndex < 0 {
 					n.Attr = append(n.Attr, html.Attribute{Key: "style", Val: styleAttr.Val})
+					added += attributeSize(html.Attribute{Key: "style"})
 				} else {
 					n.Attr[styleIndex] = styleAttr
 				}
@@ -160,11 +360,12 @@ func inlineCSS(ctx *component.RenderContext, r io.Reader, w io.Writer) error {
 		}
 	}
 
-	if err := html.Render(w, htmlNode); err != nil {
-		return err
-	}
+	return added, nil
+}
 
-	return nil
+// attributeSize returns the number of bytes attr takes up when rendered as ` key="val"`.
+func attributeSize(attr html.Attribute) int {
+	return len(attr.Key) + len(attr.Val) + 4
 }
 
 func parseStyleAttribute(attr html.Attribute) (map[string]string, error) {
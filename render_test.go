@@ -2,6 +2,9 @@ package mjmlgo
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
@@ -44,10 +47,11 @@ func TestInlineCSS(t *testing.T) {
 		}
 
 		var out bytes.Buffer
-		err := inlineCSS(ctx, strings.NewReader(input), &out)
+		added, err := inlineCSS(ctx, strings.NewReader(input), &out)
 		require.NoError(t, err)
 
 		require.Equal(t, "<html><head></head><body><p class=\"p-class\" style=\"font-size:22px;\">Hello</p></body></html>", out.String())
+		require.Equal(t, len(` style="font-size:22px;"`), added)
 	})
 
 	t.Run("important-override", func(t *testing.T) {
@@ -68,7 +72,7 @@ func TestInlineCSS(t *testing.T) {
 		}
 
 		var out bytes.Buffer
-		err := inlineCSS(ctx, strings.NewReader(input), &out)
+		_, err := inlineCSS(ctx, strings.NewReader(input), &out)
 		require.NoError(t, err)
 
 		randomOrder := []string{
@@ -79,3 +83,433 @@ func TestInlineCSS(t *testing.T) {
 		assert.True(t, slices.Contains(randomOrder, out.String()), "returned value should be one of the possible")
 	})
 }
+
+func TestRenderMJMLLinkRewriter(t *testing.T) {
+	t.Parallel()
+
+	const input = `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-image src="https://example.com/logo.png" href="https://example.com/home"></mj-image>
+        <mj-button css-class="cta" href="https://example.com/buy">Buy</mj-button>
+        <mj-button href="mailto:hello@example.com">Mail us</mj-button>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	var infos []component.LinkInfo
+	out, err := RenderMJML(strings.NewReader(input), WithLinkRewriter(func(href string, info component.LinkInfo) string {
+		infos = append(infos, info)
+		return "https://track.example.com/?u=" + href
+	}, "mailto", "tel"))
+	require.NoError(t, err)
+
+	require.Contains(t, out, `href="https://track.example.com/?u=https://example.com/home"`)
+	require.Contains(t, out, `href="https://track.example.com/?u=https://example.com/buy"`)
+	require.Contains(t, out, `href="mailto:hello@example.com"`)
+	require.Equal(t, []component.LinkInfo{
+		{Component: "mj-image", Position: 0},
+		{Component: "mj-button", CSSClass: "cta", Position: 1},
+	}, infos)
+
+	t.Run("social-element", func(t *testing.T) {
+		const input = `<mjml><mj-body><mj-section><mj-column>
+  <mj-button href="tel:+491234">Call us</mj-button>
+  <mj-social><mj-social-element name="github" href="https://github.com/example">GitHub</mj-social-element></mj-social>
+</mj-column></mj-section></mj-body></mjml>`
+
+		out, err := RenderMJML(strings.NewReader(input), WithLinkRewriter(func(_ string, info component.LinkInfo) string {
+			return fmt.Sprintf("https://t/%d", info.Position)
+		}, "tel"))
+		require.NoError(t, err)
+
+		// the icon and the text share one rewritten link, the skipped tel: link does not use up a position
+		require.Equal(t, 2, strings.Count(out, `href="https://t/0"`))
+		require.NotContains(t, out, "https://t/1")
+		require.Contains(t, out, `href="tel:+491234"`)
+	})
+}
+
+func TestRenderMJMLHooks(t *testing.T) {
+	t.Parallel()
+
+	const input = `<mjml lang="en">
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text>Hello</mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	out, err := RenderMJML(strings.NewReader(input),
+		WithHeadHook(func(_ *component.RenderContext, w io.Writer) error {
+			_, err := io.WriteString(w, `<meta name="x-campaign" content="42">`)
+			return err
+		}),
+		WithBeforeBodyHook(func(_ *component.RenderContext, w io.Writer) error {
+			_, err := io.WriteString(w, `<img src="https://track.example.com/open.gif" width="1" height="1" alt="">`)
+			return err
+		}),
+		WithAfterBodyHook(func(ctx *component.RenderContext, w io.Writer) error {
+			_, err := fmt.Fprintf(w, `<p id="footer">unsubscribe (%s)</p>`, ctx.Language)
+			return err
+		}),
+	)
+	require.NoError(t, err)
+
+	require.Regexp(t, `<meta name="x-campaign" content="42"/>\s*</head>`, out)
+	require.Regexp(t, `<div dir="auto" lang="en">\s*<img src="https://track.example.com/open.gif"`, out)
+	require.Regexp(t, `<p id="footer">unsubscribe \(en\)</p>\s*</div>\s*</body>`, out)
+
+	_, err = RenderMJML(strings.NewReader(input), WithAfterBodyHook(func(_ *component.RenderContext, _ io.Writer) error {
+		return errors.New("hook failed")
+	}))
+	require.Error(t, err)
+}
+
+func TestRenderMJMLWithStats(t *testing.T) {
+	t.Parallel()
+
+	const input = `<mjml>
+  <mj-head>
+    <mj-style inline="inline">.red { color: red; }</mj-style>
+  </mj-head>
+  <mj-body>
+    <mj-section css-class="header">
+      <mj-column>
+        <mj-image src="https://example.com/logo.png" href="https://example.com"></mj-image>
+      </mj-column>
+    </mj-section>
+    <mj-section>
+      <mj-column>
+        <mj-text css-class="red">Hello</mj-text>
+        <mj-button href="https://example.com/buy">Buy</mj-button>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	t.Run("stats", func(t *testing.T) {
+		out, stats, err := RenderMJMLWithStats(strings.NewReader(input))
+		require.NoError(t, err)
+
+		require.Equal(t, len(out), stats.Size)
+		require.Equal(t, 1, stats.Images)
+		require.Equal(t, 2, stats.Links)
+		require.Positive(t, stats.InlineStyleBytes)
+		require.Len(t, stats.Sections, 2)
+		require.Equal(t, "mj-section", stats.Sections[0].Type)
+		require.Equal(t, "header", stats.Sections[0].CSSClass)
+		require.Positive(t, stats.Sections[1].Size)
+		require.False(t, stats.BudgetExceeded)
+	})
+
+	t.Run("budget-warn", func(t *testing.T) {
+		out, stats, err := RenderMJMLWithStats(strings.NewReader(input), WithSizeBudget(100, false))
+		require.NoError(t, err)
+		require.NotEmpty(t, out)
+		require.True(t, stats.BudgetExceeded)
+		require.Equal(t, 100, stats.Budget)
+	})
+
+	t.Run("budget-strict", func(t *testing.T) {
+		_, err := RenderMJML(strings.NewReader(input), WithSizeBudget(100, true))
+		require.ErrorIs(t, err, ErrSizeBudgetExceeded)
+
+		_, err = RenderMJML(strings.NewReader(input), WithSizeBudget(GmailClipSize, true))
+		require.NoError(t, err)
+	})
+}
+
+func TestRenderMJMLNamelessFont(t *testing.T) {
+	t.Parallel()
+
+	const input = `<mjml>
+  <mj-head>
+    <mj-font href="https://example.com/font.css" />
+  </mj-head>
+  <mj-body><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section></mj-body>
+</mjml>`
+
+	_, err := RenderMJML(strings.NewReader(input))
+	var validationErr *component.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.ErrorIs(t, err, component.ErrValidation)
+	require.Equal(t, 3, validationErr.Line)
+
+	out, stats, err := RenderMJMLWithStats(strings.NewReader(input), WithValidationLevel(component.ValidationSoft))
+	require.NoError(t, err)
+	require.Contains(t, out, "Hello")
+	require.NotContains(t, out, "font.css")
+	require.Len(t, stats.ValidationErrors, 1)
+	require.Equal(t, "mj-font", stats.ValidationErrors[0].Tag)
+
+	out, stats, err = RenderMJMLWithStats(strings.NewReader(input), WithValidationLevel(component.ValidationSkip))
+	require.NoError(t, err)
+	require.Contains(t, out, "Hello")
+	require.Empty(t, stats.ValidationErrors)
+}
+
+func TestRenderMJMLClass(t *testing.T) {
+	t.Parallel()
+
+	out, err := RenderMJML(strings.NewReader(`<mjml>
+  <mj-head>
+    <mj-attributes>
+      <mj-text color="#0000ff" />
+      <mj-class name="red" color="#ff0000" font-size="20px" />
+      <mj-class name="small" font-size="10px" />
+    </mj-attributes>
+  </mj-head>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text mj-class="red small">Both</mj-text>
+        <mj-text mj-class="red" color="#00ff00">Inline</mj-text>
+        <mj-text>Plain</mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`))
+	require.NoError(t, err)
+
+	require.Contains(t, out, `font-size:10px;line-height:1;color:#ff0000;">Both<`)
+	require.Contains(t, out, `font-size:20px;line-height:1;color:#00ff00;">Inline<`)
+	require.Contains(t, out, `font-size:13px;line-height:1;color:#0000ff;">Plain<`)
+}
+
+func TestRenderMJMLAttributeWithoutValue(t *testing.T) {
+	t.Parallel()
+
+	out, err := RenderMJML(strings.NewReader(`<mjml><mj-body><mj-section><mj-column><mj-image src="a.png" fluid-on-mobile></mj-image></mj-column></mj-section></mj-body></mjml>`))
+	require.NoError(t, err)
+	require.Contains(t, out, `class="mj-full-width-mobile"`)
+}
+
+func TestRenderMJMLEscaping(t *testing.T) {
+	t.Parallel()
+
+	const input = `<mjml lang="en&quot; onload=&quot;x">
+  <mj-head>
+    <mj-title>Tom &amp; Jerry &lt;/title&gt;</mj-title>
+    <mj-preview>&lt;script&gt;alert(1)&lt;/script&gt;</mj-preview>
+    <mj-font name="A" href="https://fonts.example.com/css?family=A&amp;display=swap&quot;);&lt;/style&gt;&lt;script&gt;alert(1)&lt;/script&gt;" />
+  </mj-head>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-image src="https://example.com/a.png?a=1&amp;b=2" alt="&quot; onerror=&quot;alert(1)" />
+        <mj-text><a href="https://example.com/?q=&#34;x&#34;" title="a &lt; b">Link</a> <b>bold</b></mj-text>
+      </mj-column>
+    </mj-section>
+    <mj-raw><p data-raw="1">raw</p></mj-raw>
+  </mj-body>
+</mjml>`
+
+	out, err := RenderMJML(strings.NewReader(input))
+	require.NoError(t, err)
+
+	require.Contains(t, out, `<html lang="en&#34; onload=&#34;x"`)
+	require.Contains(t, out, "<title>Tom &amp; Jerry &lt;/title&gt;</title>")
+	require.Contains(t, out, "&lt;script&gt;alert(1)&lt;/script&gt;</div>")
+	require.NotContains(t, out, "<script>")
+	require.Contains(t, out, `alt="&#34; onerror=&#34;alert(1)"`)
+	require.NotContains(t, out, ` onerror="`)
+	require.Contains(t, out, `src="https://example.com/a.png?a=1&amp;b=2"`)
+	require.Contains(t, out, `<a href="https://example.com/?q=&#34;x&#34;" title="a &lt; b">Link</a> <b>bold</b>`)
+	require.Contains(t, out, `<p data-raw="1">raw</p>`)
+	require.Contains(t, out, `@import url("https://fonts.example.com/css?family=A&display=swap\22 );\3c /style\3e \3c script\3e alert(1)\3c /script\3e ");`)
+}
+
+func TestRenderMJMLFileStartRaw(t *testing.T) {
+	t.Parallel()
+
+	const input = `<mj-raw position="file-start">{% extends "base.html" %}</mj-raw>
+<mjml>
+  <mj-raw position="file-start">{% load static %}</mj-raw>
+  <mj-head>
+    <mj-raw position="file-start">{# head #}</mj-raw>
+  </mj-head>
+  <mj-body>
+    <mj-raw position="file-start">{# body #}</mj-raw>
+    <mj-raw><p>inline raw</p></mj-raw>
+  </mj-body>
+</mjml>`
+
+	out, err := RenderMJML(strings.NewReader(input))
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(out, "{% extends \"base.html\" %}\n{% load static %}\n{# head #}\n{# body #}\n<!DOCTYPE html>"), out[:100])
+	require.Equal(t, 1, strings.Count(out, "{# body #}"))
+	require.Equal(t, 1, strings.Count(out, "{# head #}"))
+	require.Contains(t, out, "<p>inline raw</p>")
+
+	_, err = RenderMJML(strings.NewReader(`<mjml><mj-body><mj-raw position="file-end">x</mj-raw></mj-body></mjml>`))
+	require.ErrorIs(t, err, component.ErrValidation)
+}
+
+func TestRenderMJMLPreservesRawContent(t *testing.T) {
+	t.Parallel()
+
+	const content = `a &lt;b&gt; &amp; c&nbsp;d<br/>e <![CDATA[x < y & z]]> &#169;`
+
+	out, err := RenderMJML(strings.NewReader(`<mjml><mj-body><mj-section><mj-column>` +
+		`<mj-text>` + content + `</mj-text>` +
+		`<mj-button href="#">Buy&nbsp;now &amp; save<br/></mj-button>` +
+		`</mj-column></mj-section></mj-body></mjml>`))
+	require.NoError(t, err)
+
+	require.Contains(t, out, content)
+	require.Contains(t, out, "Buy&nbsp;now &amp; save<br/>")
+
+	t.Run("inline-styles", func(t *testing.T) {
+		const doc = `<mjml><mj-head>%s</mj-head><mj-body><mj-section><mj-column>` +
+			`<mj-text css-class="x"><p class="y">` + content + `</p></mj-text>` +
+			`</mj-column></mj-section></mj-body></mjml>`
+
+		plain, err := RenderMJML(strings.NewReader(fmt.Sprintf(doc, "")))
+		require.NoError(t, err)
+		inlined, err := RenderMJML(strings.NewReader(fmt.Sprintf(doc, `<mj-style inline="inline">.y { color: red; }</mj-style>`)))
+		require.NoError(t, err)
+
+		require.Contains(t, inlined, `<p class="y" style="color:red;">`+content+`</p>`)
+		require.Equal(t, plain, strings.Replace(inlined, ` style="color:red;"`, "", 1))
+	})
+
+	t.Run("cdata-with-markup", func(t *testing.T) {
+		out, err := RenderMJML(strings.NewReader(`<mjml><mj-body><mj-section><mj-column>` +
+			`<mj-text><![CDATA[<x>]]></mj-text><mj-text><![CDATA[<p class="a">1 > 0</p>]]></mj-text>` +
+			`</mj-column></mj-section></mj-body></mjml>`))
+		require.NoError(t, err)
+
+		require.Contains(t, out, `<![CDATA[<x>]]>`)
+		require.Contains(t, out, `<![CDATA[<p class="a">1 > 0</p>]]>`)
+	})
+}
+
+func TestRenderMJMLComments(t *testing.T) {
+	t.Parallel()
+
+	const input = `<mjml>
+  <mj-body>
+    <!-- BEGIN:loop -->
+    <mj-wrapper>
+      <!-- wrapper -->
+      <mj-section>
+        <!-- section -->
+        <mj-group>
+          <!-- group -->
+          <mj-column>
+            <!-- column -->
+            <mj-text>Hello <!-- text --></mj-text>
+          </mj-column>
+        </mj-group>
+      </mj-section>
+    </mj-wrapper>
+    <!-- END:loop -->
+  </mj-body>
+</mjml>`
+
+	out, err := RenderMJML(strings.NewReader(input))
+	require.NoError(t, err)
+
+	for _, comment := range []string{"BEGIN:loop", "wrapper", "section", "group", "column", "END:loop"} {
+		require.Contains(t, out, "<!-- "+comment+" -->")
+	}
+	require.Less(t, strings.Index(out, "<!-- BEGIN:loop -->"), strings.Index(out, "Hello"))
+	require.Greater(t, strings.Index(out, "<!-- END:loop -->"), strings.Index(out, "Hello"))
+
+	out, err = RenderMJML(strings.NewReader(input), WithKeepComments(false))
+	require.NoError(t, err)
+	require.NotContains(t, out, "loop")
+	require.Contains(t, out, "Hello <!-- text -->")
+
+	// every container renders comments in place
+	for name, body := range map[string]string{
+		"body":              `<!-- c --><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section>`,
+		"wrapper":           `<mj-wrapper><!-- c --><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section></mj-wrapper>`,
+		"section":           `<mj-section><!-- c --><mj-column><mj-text>Hello</mj-text></mj-column></mj-section>`,
+		"group":             `<mj-section><mj-group><!-- c --><mj-column><mj-text>Hello</mj-text></mj-column></mj-group></mj-section>`,
+		"column":            `<mj-section><mj-column><!-- c --><mj-text>Hello</mj-text></mj-column></mj-section>`,
+		"hero":              `<mj-hero background-width="600px" background-height="300px"><!-- c --><mj-text>Hello</mj-text></mj-hero>`,
+		"social-horizontal": `<mj-section><mj-column><mj-social><!-- c --><mj-social-element name="github">Hello</mj-social-element></mj-social></mj-column></mj-section>`,
+		"social-vertical":   `<mj-section><mj-column><mj-social mode="vertical"><!-- c --><mj-social-element name="github">Hello</mj-social-element></mj-social></mj-column></mj-section>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			out, err := RenderMJML(strings.NewReader(`<mjml><mj-body>` + body + `</mj-body></mjml>`))
+			require.NoError(t, err)
+			require.Contains(t, out, "<!-- c -->")
+			require.Less(t, strings.Index(out, "<!-- c -->"), strings.Index(out, "Hello"))
+		})
+	}
+
+	t.Run("head", func(t *testing.T) {
+		out, err := RenderMJML(strings.NewReader(`<mjml><mj-head><!-- c --></mj-head><mj-body></mj-body></mjml>`))
+		require.NoError(t, err)
+		require.Less(t, strings.Index(out, "<!-- c -->"), strings.Index(out, "</head>"))
+	})
+}
+
+func TestRenderMJMLRecovery(t *testing.T) {
+	t.Parallel()
+
+	const input = `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text>Hello</mj-text>
+        <mj-button href="https://example.com">Click`
+
+	_, err := RenderMJML(strings.NewReader(input))
+	require.ErrorIs(t, err, ErrParsingFailed)
+
+	out, stats, err := RenderMJMLWithStats(strings.NewReader(input), WithRecovery())
+	require.NoError(t, err)
+	require.Contains(t, out, "Hello")
+	require.Contains(t, out, "Click")
+	require.Len(t, stats.Recovered, 5)
+	require.Equal(t, "<mj-button> is not closed", stats.Recovered[0].Msg)
+	require.Equal(t, 6, stats.Recovered[0].Line)
+
+	t.Run("validation", func(t *testing.T) {
+		const input = `<mjml><mj-body><mj-section><mj-column><mj-text color="not-a-color">Hello`
+
+		out, stats, err := RenderMJMLWithStats(strings.NewReader(input), WithRecovery())
+		require.NoError(t, err)
+		require.Contains(t, out, "Hello")
+		require.Len(t, stats.ValidationErrors, 1)
+
+		_, err = RenderMJML(strings.NewReader(input), WithRecovery(), WithValidationLevel(component.ValidationStrict))
+		require.ErrorIs(t, err, component.ErrValidation)
+	})
+}
+
+func TestRenderMJMLSourceMap(t *testing.T) {
+	t.Parallel()
+
+	const input = `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text>Hello</mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	out, err := RenderMJML(strings.NewReader(input))
+	require.NoError(t, err)
+	require.NotContains(t, out, component.SourceMapAttribute)
+
+	out, err = RenderMJML(strings.NewReader(input), WithSourceMap())
+	require.NoError(t, err)
+	require.Contains(t, out, `<body data-mj-node="2:3"`)
+	require.Contains(t, out, `<div data-mj-node="3:5" style="margin:0px auto;max-width:600px;">`)
+	require.Contains(t, out, `<div data-mj-node="4:7" class="mj-column-per-100 mj-outlook-group-fix"`)
+	require.Regexp(t, `<div data-mj-node="5:9" style="[^"]*">Hello</div>`, out)
+	require.Equal(t, 4, strings.Count(out, component.SourceMapAttribute))
+}
@@ -0,0 +1,276 @@
+package mjmlgo
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/julez-dev/mjmlgo/component"
+	"github.com/julez-dev/mjmlgo/node"
+	"golang.org/x/net/html"
+)
+
+// SafePolicy configures the safe mode enabled with WithSafeMode, used to render MJML written by untrusted users.
+// The zero value uses the defaults of all fields.
+type SafePolicy struct {
+	// AllowedSchemes are the URL schemes allowed in href and src attributes, defaults to http, https, mailto and tel.
+	// Relative URLs are always allowed.
+	AllowedSchemes []string
+	// MaxDepth is the maximum nesting depth of elements, defaults to 32.
+	MaxDepth int
+	// MaxInputSize is the maximum size of the MJML input in bytes, defaults to 1 MiB.
+	MaxInputSize int
+	// AllowRaw keeps <mj-raw> elements, their content is sanitized like the content of <mj-text>.
+	// By default they are removed.
+	AllowRaw bool
+}
+
+func (p SafePolicy) allowedSchemes() []string {
+	if len(p.AllowedSchemes) == 0 {
+		return []string{"http", "https", "mailto", "tel"}
+	}
+	return p.AllowedSchemes
+}
+
+func (p SafePolicy) maxDepth() int {
+	if p.MaxDepth <= 0 {
+		return 32
+	}
+	return p.MaxDepth
+}
+
+func (p SafePolicy) maxInputSize() int {
+	if p.MaxInputSize <= 0 {
+		return 1 << 20
+	}
+	return p.MaxInputSize
+}
+
+// Removal describes content removed by safe mode.
+type Removal struct {
+	// Tag is the MJML element the content was removed from or, if the element was removed, the element itself.
+	Tag string
+	// Line and Column are the position of the element in the MJML source, 0 if unknown.
+	Line   int
+	Column int
+	// Reason describes what was removed, e.g. `attribute onclick of <a>`.
+	Reason string
+}
+
+func (r Removal) String() string {
+	if r.Line > 0 {
+		return fmt.Sprintf("%d:%d: <%s>: removed %s", r.Line, r.Column, r.Tag, r.Reason)
+	}
+	return fmt.Sprintf("<%s>: removed %s", r.Tag, r.Reason)
+}
+
+// urlAttributes are attributes of MJML components and HTML elements containing URLs.
+var urlAttributes = map[string]struct{}{
+	"href":           {},
+	"src":            {},
+	"background-url": {},
+	"background":     {},
+	"action":         {},
+	"formaction":     {},
+	"poster":         {},
+	"xlink:href":     {},
+}
+
+// unsafeElements are HTML elements removed from content. If the value is true, their content is removed as well.
+var unsafeElements = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"iframe":   true,
+	"noscript": true,
+	"object":   true,
+	"embed":    true,
+	"applet":   true,
+	"frame":    true,
+	"frameset": true,
+	"base":     false,
+	"link":     false,
+	"meta":     false,
+	"form":     false,
+	// SVG animations can set attributes like href to a javascript: URL with their values, from and to attributes
+	"animate":          false,
+	"animatemotion":    false,
+	"animatetransform": false,
+	"set":              false,
+}
+
+// sanitizer removes unsafe content from a node tree according to a SafePolicy.
+type sanitizer struct {
+	policy  SafePolicy
+	removed []Removal
+}
+
+// sanitize removes unsafe elements, attributes and URLs from root and returns what was removed.
+func sanitize(root *node.Node, policy SafePolicy) ([]Removal, error) {
+	s := &sanitizer{policy: policy}
+	if err := s.sanitizeNode(root, 1); err != nil {
+		return nil, err
+	}
+
+	return s.removed, nil
+}
+
+func (s *sanitizer) remove(n *node.Node, format string, args ...any) {
+	s.removed = append(s.removed, Removal{
+		Tag:    n.Type,
+		Line:   n.Line,
+		Column: n.Column,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+func (s *sanitizer) sanitizeNode(n *node.Node, depth int) error {
+	if depth > s.policy.maxDepth() {
+		return fmt.Errorf("%w: <%s> exceeds %d levels", ErrNestingTooDeep, n.Type, s.policy.maxDepth())
+	}
+
+	for _, attr := range slices.Clone(n.Attributes) {
+		name := node.AttributeName(attr)
+		if _, ok := urlAttributes[name]; ok && !s.allowedURL(attr.Value) {
+			n.RemoveAttribute(name)
+			s.remove(n, "attribute %s with URL %q", name, attr.Value)
+		}
+	}
+
+	switch n.Type {
+	case component.RawTagName:
+		if !s.policy.AllowRaw {
+			n.Remove()
+			// comments are dropped without being reported as removed elements
+			if !component.IsComment(n) {
+				s.remove(n, "element")
+			}
+			return nil
+		}
+		n.Content = s.sanitizeHTML(n, n.Content)
+	case component.StyleTagName:
+		lower := strings.ToLower(n.Content)
+		if strings.Contains(lower, "</") || strings.Contains(lower, "expression(") || strings.Contains(lower, "javascript:") {
+			n.Remove()
+			s.remove(n, "element with unsafe CSS")
+			return nil
+		}
+	case component.FontTagName:
+		if strings.ContainsAny(n.GetAttributeValueDefault("href"), `<>()"'`) {
+			n.Remove()
+			s.remove(n, "element with unsafe href")
+			return nil
+		}
+	default:
+		if _, ok := mjmlEndTags[n.Type]; ok {
+			n.Content = s.sanitizeHTML(n, n.Content)
+		}
+	}
+
+	// iterate over a copy, sanitizeNode may remove children
+	for _, child := range slices.Clone(n.Children) {
+		if err := s.sanitizeNode(child, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sanitizeHTML removes unsafe elements, event handler attributes and URLs from the HTML content of n.
+// Tokens that are not changed are written unmodified.
+func (s *sanitizer) sanitizeHTML(n *node.Node, content string) string {
+	var (
+		b    strings.Builder
+		z    = html.NewTokenizer(strings.NewReader(content))
+		skip string // name of the unsafe element whose content is skipped
+	)
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return b.String()
+		}
+
+		raw := string(z.Raw())
+
+		if skip != "" {
+			if name, _ := z.TagName(); tt == html.EndTagToken && string(name) == skip {
+				skip = ""
+			}
+			continue
+		}
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken && tt != html.EndTagToken {
+			b.WriteString(raw)
+			continue
+		}
+
+		token := z.Token()
+		if dropContent, ok := unsafeElements[token.Data]; ok {
+			if tt != html.EndTagToken {
+				s.remove(n, "<%s> element", token.Data)
+			}
+			if tt == html.StartTagToken && dropContent {
+				skip = token.Data
+			}
+			continue
+		}
+
+		changed := false
+		token.Attr = slices.DeleteFunc(token.Attr, func(attr html.Attribute) bool {
+			key := strings.ToLower(attr.Key)
+			if attr.Namespace != "" {
+				key = attr.Namespace + ":" + key
+			}
+
+			switch {
+			case strings.HasPrefix(key, "on"):
+				s.remove(n, "attribute %s of <%s>", attr.Key, token.Data)
+			case key == "srcdoc":
+				s.remove(n, "attribute %s of <%s>", attr.Key, token.Data)
+			case key == "style" && unsafeStyle(attr.Val):
+				s.remove(n, "attribute %s of <%s> with unsafe CSS", attr.Key, token.Data)
+			default:
+				if _, ok := urlAttributes[key]; ok && !s.allowedURL(attr.Val) {
+					s.remove(n, "attribute %s of <%s> with URL %q", attr.Key, token.Data, attr.Val)
+					break
+				}
+				return false
+			}
+
+			changed = true
+			return true
+		})
+
+		if changed {
+			b.WriteString(token.String())
+		} else {
+			b.WriteString(raw)
+		}
+	}
+}
+
+func unsafeStyle(style string) bool {
+	lower := strings.ToLower(style)
+	return strings.Contains(lower, "expression(") || strings.Contains(lower, "javascript:")
+}
+
+// allowedURL reports whether rawURL is relative or uses one of the allowed schemes.
+func (s *sanitizer) allowedURL(rawURL string) bool {
+	// browsers ignore whitespace and control characters in the scheme, e.g. "java\tscript:"
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, rawURL)
+
+	scheme, _, found := strings.Cut(cleaned, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+
+	return slices.ContainsFunc(s.policy.allowedSchemes(), func(allowed string) bool {
+		return strings.EqualFold(allowed, scheme)
+	})
+}
@@ -0,0 +1,109 @@
+package mjmlgo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeMode(t *testing.T) {
+	t.Parallel()
+
+	const input = `<mjml>
+  <mj-head>
+    <mj-style>.a { color: red; }</mj-style>
+    <mj-style>.b { color: red; } &lt;/style&gt;&lt;script&gt;alert(1)&lt;/script&gt;</mj-style>
+  </mj-head>
+  <mj-body>
+    <mj-raw><script>alert(1)</script></mj-raw>
+    <mj-section background-url="javascript:alert(1)">
+      <mj-column>
+        <mj-text><p onclick="alert(1)" class="x">Hi <a href="java&#x09;script:alert(1)">link</a> <a href="https://example.com">ok</a></p><script>alert(2)</script><img src="/a.png" onerror="alert(3)" /></mj-text>
+        <mj-button href="JavaScript:alert(1)">Click</mj-button>
+        <mj-image src="https://example.com/a.png" href="mailto:info@example.com" />
+        <mj-table><tr><td style="width:expression(alert(1))">x</td></tr></mj-table>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	out, stats, err := RenderMJMLWithStats(strings.NewReader(input), WithSafeMode(SafePolicy{}))
+	require.NoError(t, err)
+
+	require.NotContains(t, out, "alert")
+	require.NotContains(t, strings.ToLower(out), "javascript")
+	require.Contains(t, out, `<p class="x">Hi <a>link</a> <a href="https://example.com">ok</a></p>`)
+	require.Contains(t, out, `<img src="/a.png"/>`)
+	require.Contains(t, out, `href="mailto:info@example.com"`)
+	require.Contains(t, out, ".a {")
+
+	var reasons []string
+	for _, r := range stats.Removed {
+		reasons = append(reasons, r.String())
+	}
+	require.Equal(t, []string{
+		"4:5: <mj-style>: removed element with unsafe CSS",
+		"7:5: <mj-raw>: removed element",
+		`8:5: <mj-section>: removed attribute background-url with URL "javascript:alert(1)"`,
+		"10:9: <mj-text>: removed attribute onclick of <p>",
+		`10:9: <mj-text>: removed attribute href of <a> with URL "java\tscript:alert(1)"`,
+		"10:9: <mj-text>: removed <script> element",
+		"10:9: <mj-text>: removed attribute onerror of <img>",
+		`11:9: <mj-button>: removed attribute href with URL "JavaScript:alert(1)"`,
+		"13:9: <mj-table>: removed attribute style of <td> with unsafe CSS",
+	}, reasons)
+
+	t.Run("allow-raw", func(t *testing.T) {
+		out, err := RenderMJML(strings.NewReader(`<mjml><mj-body><mj-raw><p onmouseover="alert(1)">raw</p></mj-raw></mj-body></mjml>`),
+			WithSafeMode(SafePolicy{AllowRaw: true}))
+		require.NoError(t, err)
+		require.Contains(t, out, "<p>raw</p>")
+	})
+
+	t.Run("comments", func(t *testing.T) {
+		out, stats, err := RenderMJMLWithStats(strings.NewReader(`<mjml><mj-body><!-- c --><mj-raw><p>raw</p></mj-raw></mj-body></mjml>`),
+			WithSafeMode(SafePolicy{}))
+		require.NoError(t, err)
+		require.NotContains(t, out, "<!-- c -->")
+		require.Len(t, stats.Removed, 1)
+		require.Equal(t, "1:26: <mj-raw>: removed element", stats.Removed[0].String())
+	})
+
+	t.Run("svg-animation", func(t *testing.T) {
+		out, stats, err := RenderMJMLWithStats(strings.NewReader(`<mjml><mj-body><mj-section><mj-column><mj-text>`+
+			`<svg><a><animate attributeName="href" values="javascript:alert(1)"/><set attributeName="href" to="javascript:alert(2)"/>`+
+			`<animateTransform attributeName="transform" from="0" to="1"/><text>x</text></a></svg>`+
+			`</mj-text></mj-column></mj-section></mj-body></mjml>`), WithSafeMode(SafePolicy{}))
+		require.NoError(t, err)
+		require.NotContains(t, out, "alert")
+		require.NotContains(t, strings.ToLower(out), "<animate")
+		require.Contains(t, out, "<text>x</text>")
+		require.Len(t, stats.Removed, 3)
+	})
+
+	t.Run("schemes", func(t *testing.T) {
+		out, err := RenderMJML(strings.NewReader(`<mjml><mj-body><mj-section><mj-column><mj-button href="http://example.com">A</mj-button><mj-button href="https://example.com/b">B</mj-button></mj-column></mj-section></mj-body></mjml>`),
+			WithSafeMode(SafePolicy{AllowedSchemes: []string{"https"}}))
+		require.NoError(t, err)
+		require.NotContains(t, out, "http://example.com")
+		require.Contains(t, out, "https://example.com/b")
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		_, err := RenderMJML(strings.NewReader(input), WithSafeMode(SafePolicy{MaxInputSize: 100}))
+		require.ErrorIs(t, err, ErrInputTooLarge)
+		require.ErrorIs(t, err, ErrParsingFailed)
+
+		_, err = RenderMJML(strings.NewReader(input), WithSafeMode(SafePolicy{MaxDepth: 4}))
+		require.ErrorIs(t, err, ErrNestingTooDeep)
+
+		_, err = Parse(strings.NewReader(input), WithSafeMode(SafePolicy{MaxDepth: 4}))
+		require.ErrorIs(t, err, ErrNestingTooDeep)
+
+		root, err := Parse(strings.NewReader(input))
+		require.NoError(t, err)
+		_, err = RenderNode(root, WithSafeMode(SafePolicy{MaxDepth: 4}))
+		require.ErrorIs(t, err, ErrNestingTooDeep)
+	})
+}
@@ -0,0 +1,100 @@
+package mjmlgo
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/julez-dev/mjmlgo/component"
+	"github.com/julez-dev/mjmlgo/node"
+)
+
+// SerializeOption configures the behaviour of Serialize.
+type SerializeOption func(*serializeOptions)
+
+type serializeOptions struct {
+	indent string
+}
+
+// WithIndent pretty-prints the output, every nesting level is indented with indent.
+// The content of mj-raw and ending tags like mj-text is always written unchanged.
+func WithIndent(indent string) SerializeOption {
+	return func(o *serializeOptions) {
+		o.indent = indent
+	}
+}
+
+var (
+	textEscaper      = strings.NewReplacer("&", "&amp;", "<", "&lt;")
+	attributeEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;")
+)
+
+// Serialize writes the node tree as MJML markup to w.
+// Attributes keep their order, the content of mj-raw and ending tags like mj-text is written as is,
+// so parsing the output results in the same tree.
+//
+// Text directly inside other elements, e.g. <mj-column>text<mj-image/>more</mj-column>, is kept in Content
+// without its position among the children, so it is written before the first child. MJML does not render
+// such text, the tree is the same after parsing the output again.
+func Serialize(w io.Writer, n *node.Node, opts ...SerializeOption) error {
+	o := &serializeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	bw := bufio.NewWriter(w)
+	serializeNode(bw, n, o, 0)
+	if o.indent != "" {
+		bw.WriteByte('\n')
+	}
+
+	return bw.Flush()
+}
+
+func serializeNode(w *bufio.Writer, n *node.Node, o *serializeOptions, depth int) {
+	if o.indent != "" && depth > 0 {
+		w.WriteByte('\n')
+		w.WriteString(strings.Repeat(o.indent, depth))
+	}
+
+	// comments are parsed into mj-raw elements, they are written back as comments
+	if component.IsComment(n) {
+		w.WriteString(n.Content)
+		return
+	}
+
+	w.WriteString("<" + n.Type)
+	for _, attr := range n.Attributes {
+		w.WriteString(" " + node.AttributeName(attr) + `="`)
+		attributeEscaper.WriteString(w, attr.Value)
+		w.WriteByte('"')
+	}
+
+	_, isEndTag := mjmlEndTags[n.Type]
+	isRaw := n.Type == component.RawTagName
+
+	// mj-raw is matched by its closing tag, so it is never written as self-closing.
+	if n.Content == "" && len(n.Children) == 0 && !isRaw {
+		w.WriteString(" />")
+		return
+	}
+
+	w.WriteByte('>')
+
+	if isEndTag || isRaw {
+		w.WriteString(n.Content)
+	} else {
+		textEscaper.WriteString(w, n.Content)
+	}
+
+	for _, child := range n.Children {
+		serializeNode(w, child, o, depth+1)
+	}
+
+	if o.indent != "" && len(n.Children) > 0 {
+		w.WriteByte('\n')
+		w.WriteString(strings.Repeat(o.indent, depth))
+	}
+
+	w.WriteString("</" + n.Type + ">")
+}
@@ -0,0 +1,113 @@
+package mjmlgo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julez-dev/mjmlgo/node"
+	"github.com/stretchr/testify/require"
+)
+
+func requireSameTree(t *testing.T, expected, actual *node.Node) {
+	t.Helper()
+
+	require.Equal(t, expected.Type, actual.Type)
+	if len(expected.Attributes) > 0 || len(actual.Attributes) > 0 {
+		require.Equal(t, expected.Attributes, actual.Attributes, "attributes of <%s>", expected.Type)
+	}
+	require.Equal(t, expected.Content, actual.Content, "content of <%s>", expected.Type)
+	require.Len(t, actual.Children, len(expected.Children), "children of <%s>", expected.Type)
+	for i := range expected.Children {
+		requireSameTree(t, expected.Children[i], actual.Children[i])
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("output", func(t *testing.T) {
+		const input = `<mjml><mj-head><mj-title>A &amp; B</mj-title></mj-head><mj-body><mj-raw><p>raw &nbsp; content</p></mj-raw>` +
+			`<mj-section padding="0" background-color="#fff"><mj-column><mj-image src="a.png?a=1&amp;b=2" alt="&quot;x&quot;" />` +
+			`<mj-text color="red"><b>Hello</b> <br/></mj-text></mj-column></mj-section></mj-body></mjml>`
+
+		root, err := Parse(strings.NewReader(input))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, Serialize(&buf, root))
+		require.Equal(t, `<mjml><mj-head><mj-title>A &amp; B</mj-title></mj-head><mj-body><mj-raw><p>raw &nbsp; content</p></mj-raw>`+
+			`<mj-section padding="0" background-color="#fff"><mj-column><mj-image src="a.png?a=1&amp;b=2" alt="&quot;x&quot;" />`+
+			`<mj-text color="red"><b>Hello</b> <br/></mj-text></mj-column></mj-section></mj-body></mjml>`, buf.String())
+
+		buf.Reset()
+		require.NoError(t, Serialize(&buf, root, WithIndent("  ")))
+		require.Equal(t, `<mjml>
+  <mj-head>
+    <mj-title>A &amp; B</mj-title>
+  </mj-head>
+  <mj-body>
+    <mj-raw><p>raw &nbsp; content</p></mj-raw>
+    <mj-section padding="0" background-color="#fff">
+      <mj-column>
+        <mj-image src="a.png?a=1&amp;b=2" alt="&quot;x&quot;" />
+        <mj-text color="red"><b>Hello</b> <br/></mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>
+`, buf.String())
+	})
+
+	t.Run("namespaced-attributes", func(t *testing.T) {
+		const input = `<mjml><mj-body><mj-section v:fill="true" href="a.html" xlink:href="b.svg" data-id="1"></mj-section></mj-body></mjml>`
+
+		root, err := Parse(strings.NewReader(input))
+		require.NoError(t, err)
+
+		section := root.Find("mj-section")[0]
+		require.Equal(t, "a.html", section.GetAttributeValueDefault("href"))
+		require.Equal(t, "b.svg", section.GetAttributeValueDefault("xlink:href"))
+
+		var buf bytes.Buffer
+		require.NoError(t, Serialize(&buf, section))
+		require.Equal(t, `<mj-section v:fill="true" href="a.html" xlink:href="b.svg" data-id="1" />`, buf.String())
+	})
+
+	t.Run("comments", func(t *testing.T) {
+		const input = `<mjml><mj-head /><mj-body><!-- BEGIN:loop --><mj-raw><!-- raw --><p>x</p></mj-raw></mj-body></mjml>`
+
+		root, err := Parse(strings.NewReader(input))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, Serialize(&buf, root))
+		require.Equal(t, input, buf.String())
+	})
+
+	t.Run("round-trip", func(t *testing.T) {
+		files, err := filepath.Glob("testdata/*.mjml")
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+
+		for _, file := range files {
+			for _, opts := range [][]SerializeOption{nil, {WithIndent("\t")}} {
+				input, err := os.ReadFile(file)
+				require.NoError(t, err)
+
+				expected, err := Parse(bytes.NewReader(input))
+				require.NoError(t, err)
+
+				var buf bytes.Buffer
+				require.NoError(t, Serialize(&buf, expected, opts...))
+
+				actual, err := Parse(&buf)
+				require.NoError(t, err, file)
+
+				requireSameTree(t, expected, actual)
+			}
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package mjmlgo
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/julez-dev/mjmlgo/component"
+	"golang.org/x/net/html"
+)
+
+// GmailClipSize is the message size in bytes above which Gmail clips an email.
+const GmailClipSize = 102 * 1024
+
+var ErrSizeBudgetExceeded = errors.New("mjml: rendered html exceeds size budget")
+
+// RenderStats describes the output of a render.
+type RenderStats struct {
+	// Size is the total size of the rendered HTML in bytes.
+	Size int
+	// Sections contains the size of each top-level <mj-body> child, measured before CSS inlining.
+	Sections []SectionStats
+	// InlineStyleBytes is the number of bytes added by inlining <mj-style inline="inline"> rules.
+	InlineStyleBytes int
+	// Images is the number of <img> elements in the rendered HTML.
+	Images int
+	// Links is the number of <a> elements with a href in the rendered HTML.
+	Links int
+
+	// Budget is the configured size budget in bytes, 0 if none is set.
+	Budget int
+	// BudgetExceeded reports whether Size is larger than Budget.
+	BudgetExceeded bool
+
+	// ValidationErrors contains the validation errors collected with component.ValidationSoft.
+	ValidationErrors []*component.ValidationError
+	// Removed contains everything removed by safe mode, see WithSafeMode.
+	Removed []Removal
+	// Recovered contains the syntax errors the parser recovered from, see WithRecovery.
+	Recovered []*SyntaxError
+}
+
+// SectionStats is the size of a single top-level section.
+type SectionStats struct {
+	Type     string
+	CSSClass string
+	Size     int
+}
+
+// countElements counts the images and links in the rendered HTML.
+// Content inside conditional comments is not taken into account.
+func countElements(s string) (images int, links int) {
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return images, links
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "img":
+				images++
+			case "a":
+				for hasAttr {
+					var key []byte
+					key, _, hasAttr = z.TagAttr()
+					if string(key) == "href" {
+						links++
+						break
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,429 @@
+package mjmlgo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/julez-dev/mjmlgo/node"
+)
+
+// SyntaxError is returned when the MJML input is not well-formed.
+type SyntaxError struct {
+	Msg string
+	// Line and Column are the 1-based position of the error in the input.
+	Line   int
+	Column int
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error on line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	startTagToken tokenKind = iota
+	endTagToken
+	textToken
+	cdataToken
+	commentToken
+)
+
+// token is a single piece of MJML markup returned by tokenizer.next.
+type token struct {
+	kind tokenKind
+	// name is the local name of a start or end tag.
+	name        string
+	attrs       []xml.Attr
+	selfClosing bool
+	// data is the decoded text, the content of a CDATA section or the content of a comment.
+	data string
+	// line and column are the 1-based position of the start of the token.
+	line   int
+	column int
+}
+
+// tokenizer splits MJML markup into tokens. It is lenient like encoding/xml in non-strict mode: attribute values
+// may be unquoted or missing, HTML entities are decoded and unknown entities are kept as is. Unlike encoding/xml it can return the content of
+// an element byte-exactly with rawContent, which is used for mj-raw and ending tags like mj-text.
+type tokenizer struct {
+	input string
+	pos   int
+	// tokenStart is the offset of the token last returned by next.
+	tokenStart int
+	// recovery ends the content returned by rawContent at the first MJML end tag, see WithRecovery.
+	recovery bool
+
+	// line and lineStart are the line number and offset of the line containing offset, used to compute positions.
+	offset    int
+	line      int
+	lineStart int
+}
+
+func newTokenizer(input string) *tokenizer {
+	return &tokenizer{input: input, line: 1}
+}
+
+// position returns the 1-based line and column of the byte at offset.
+// Offsets should be passed in increasing order, going back scans the input from the start again.
+func (z *tokenizer) position(offset int) (int, int) {
+	if offset < z.offset {
+		z.offset, z.line, z.lineStart = 0, 1, 0
+	}
+
+	for ; z.offset < offset && z.offset < len(z.input); z.offset++ {
+		if z.input[z.offset] == '\n' {
+			z.line++
+			z.lineStart = z.offset + 1
+		}
+	}
+
+	return z.line, offset - z.lineStart + 1
+}
+
+func (z *tokenizer) errorf(offset int, format string, args ...any) error {
+	line, column := z.position(offset)
+	return &SyntaxError{Msg: fmt.Sprintf(format, args...), Line: line, Column: column}
+}
+
+// next returns the next token, or io.EOF at the end of the input.
+// Processing instructions and directives like <!DOCTYPE> are skipped.
+func (z *tokenizer) next() (token, error) {
+	for z.pos < len(z.input) {
+		start := z.pos
+		z.tokenStart = start
+		line, column := z.position(start)
+		rest := z.input[start:]
+
+		switch {
+		case rest[0] != '<':
+			end := strings.IndexByte(rest, '<')
+			if end < 0 {
+				end = len(rest)
+			}
+			z.pos += end
+
+			return token{kind: textToken, data: decodeEntities(rest[:end]), line: line, column: column}, nil
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				return token{}, z.errorf(start, "unterminated comment")
+			}
+			z.pos += 4 + end + 3
+
+			return token{kind: commentToken, data: rest[4 : 4+end], line: line, column: column}, nil
+		case strings.HasPrefix(rest, "<![CDATA["):
+			end := strings.Index(rest[9:], "]]>")
+			if end < 0 {
+				return token{}, z.errorf(start, "unterminated CDATA section")
+			}
+			z.pos += 9 + end + 3
+
+			return token{kind: cdataToken, data: rest[9 : 9+end], line: line, column: column}, nil
+		case strings.HasPrefix(rest, "<!"), strings.HasPrefix(rest, "<?"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return token{}, z.errorf(start, "unexpected EOF")
+			}
+			z.pos += end + 1
+		case strings.HasPrefix(rest, "</"):
+			return z.endTag(line, column)
+		default:
+			return z.startTag(line, column)
+		}
+	}
+
+	return token{}, io.EOF
+}
+
+func (z *tokenizer) startTag(line, column int) (token, error) {
+	start := z.pos
+	z.pos++ // <
+
+	name := z.name()
+	if name == "" {
+		return token{}, z.errorf(start, "expected element name after <")
+	}
+
+	t := token{kind: startTagToken, name: localName(name), line: line, column: column}
+	for {
+		z.skipSpace()
+		if z.pos >= len(z.input) {
+			return token{}, z.errorf(z.pos, "unexpected EOF in <%s>", t.name)
+		}
+
+		switch {
+		case z.input[z.pos] == '>':
+			z.pos++
+			return t, nil
+		case strings.HasPrefix(z.input[z.pos:], "/>"):
+			z.pos += 2
+			t.selfClosing = true
+			return t, nil
+		}
+
+		attrName := z.name()
+		if attrName == "" {
+			return token{}, z.errorf(z.pos, "unexpected %q in <%s>", z.input[z.pos], t.name)
+		}
+
+		z.skipSpace()
+		if z.pos >= len(z.input) || z.input[z.pos] != '=' {
+			// an attribute without value, like a boolean HTML attribute, is true
+			t.attrs = append(t.attrs, node.NewAttribute(attrName, "true"))
+			continue
+		}
+		z.pos++ // =
+		z.skipSpace()
+
+		value, err := z.attributeValue(t.name)
+		if err != nil {
+			return token{}, err
+		}
+
+		t.attrs = append(t.attrs, node.NewAttribute(attrName, decodeEntities(value)))
+	}
+}
+
+func (z *tokenizer) attributeValue(tag string) (string, error) {
+	if z.pos >= len(z.input) {
+		return "", z.errorf(z.pos, "unexpected EOF in <%s>", tag)
+	}
+
+	if quote := z.input[z.pos]; quote == '"' || quote == '\'' {
+		end := strings.IndexByte(z.input[z.pos+1:], quote)
+		if end < 0 {
+			return "", z.errorf(z.pos, "unterminated attribute value in <%s>", tag)
+		}
+
+		value := z.input[z.pos+1 : z.pos+1+end]
+		z.pos += end + 2
+
+		return value, nil
+	}
+
+	start := z.pos
+	for z.pos < len(z.input) && !isSpace(z.input[z.pos]) && z.input[z.pos] != '>' {
+		z.pos++
+	}
+
+	return z.input[start:z.pos], nil
+}
+
+func (z *tokenizer) endTag(line, column int) (token, error) {
+	start := z.pos
+	z.pos += 2 // </
+
+	name := z.name()
+	if name == "" {
+		return token{}, z.errorf(start, "expected element name after </")
+	}
+
+	z.skipSpace()
+	if z.pos >= len(z.input) || z.input[z.pos] != '>' {
+		return token{}, z.errorf(z.pos, "expected > after </%s", name)
+	}
+	z.pos++
+
+	return token{kind: endTagToken, name: localName(name), line: line, column: column}, nil
+}
+
+// rawContent returns the content of the element tag byte-exactly, up to its matching end tag.
+// It must be called directly after the start tag was returned by next, the end tag is consumed.
+// Nested elements with the same name are balanced, comments and CDATA sections are skipped.
+// In recovery mode, the content must not contain other MJML end tags.
+func (z *tokenizer) rawContent(tag string) (string, error) {
+	start := z.pos
+	depth := 1
+
+	for i := start; ; {
+		idx := strings.IndexByte(z.input[i:], '<')
+		if idx < 0 {
+			return "", z.errorf(len(z.input), "unexpected EOF, <%s> is not closed", tag)
+		}
+		i += idx
+		rest := z.input[i:]
+
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				return "", z.errorf(i, "unterminated comment")
+			}
+			i += 4 + end + 3
+		case strings.HasPrefix(rest, "<![CDATA["):
+			end := strings.Index(rest[9:], "]]>")
+			if end < 0 {
+				return "", z.errorf(i, "unterminated CDATA section")
+			}
+			i += 9 + end + 3
+		case hasTagPrefix(rest, "</"+tag):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return "", z.errorf(i, "unexpected EOF in </%s>", tag)
+			}
+
+			depth--
+			if depth == 0 {
+				z.pos = i + end + 1
+				return z.input[start:i], nil
+			}
+			i += end + 1
+		case hasTagPrefix(rest, "<"+tag):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return "", z.errorf(i, "unexpected EOF in <%s>", tag)
+			}
+
+			if rest[end-1] != '/' {
+				depth++
+			}
+			i += end + 1
+		case z.recovery && strings.HasPrefix(rest, "</mj-"):
+			// MJML end tags can not be part of the content, the end tag of tag is missing
+			return "", z.errorf(i, "<%s> is not closed", tag)
+		default:
+			i++
+		}
+	}
+}
+
+// skipInvalid skips the markup that caused the last error of next, so tokenizing can continue in recovery mode.
+// If the markup is incomplete at the end of the input, like a tag that is still being typed, the rest of the input
+// is skipped. Otherwise only the "<" is skipped and the markup is read as text.
+func (z *tokenizer) skipInvalid() {
+	if !strings.Contains(z.input[z.tokenStart:], ">") {
+		z.pos = len(z.input)
+		return
+	}
+
+	z.pos = z.tokenStart + 1
+}
+
+// unclosedContent returns the content of a raw content element without end tag in recovery mode.
+// The content ends at the next MJML end tag, which can not be part of the content of mj-raw or an ending tag.
+func (z *tokenizer) unclosedContent() string {
+	start := z.pos
+	if end := strings.Index(z.input[start:], "</mj-"); end >= 0 {
+		z.pos = start + end
+	} else {
+		z.pos = len(z.input)
+	}
+
+	return z.input[start:z.pos]
+}
+
+// hasTagPrefix reports whether s starts with prefix followed by the end of the tag name.
+func hasTagPrefix(s, prefix string) bool {
+	if !strings.HasPrefix(s, prefix) || len(s) == len(prefix) {
+		return false
+	}
+
+	c := s[len(prefix)]
+	return isSpace(c) || c == '>' || c == '/'
+}
+
+func (z *tokenizer) name() string {
+	start := z.pos
+	for z.pos < len(z.input) && isNameByte(z.input[z.pos]) {
+		z.pos++
+	}
+
+	return z.input[start:z.pos]
+}
+
+func (z *tokenizer) skipSpace() {
+	for z.pos < len(z.input) && isSpace(z.input[z.pos]) {
+		z.pos++
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isNameByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '_' || c == ':' || c == '.' || c >= 0x80
+}
+
+// localName returns name without its namespace prefix, like encoding/xml does for element names.
+func localName(name string) string {
+	if prefix, local, ok := strings.Cut(name, ":"); ok && prefix != "" && local != "" {
+		return local
+	}
+
+	return name
+}
+
+var xmlEntities = map[string]string{
+	"amp":  "&",
+	"lt":   "<",
+	"gt":   ">",
+	"quot": `"`,
+	"apos": "'",
+}
+
+// decodeEntities replaces the predefined XML entities, the HTML entities of xml.HTMLEntity and character
+// references in s. Unknown entities are kept as is.
+func decodeEntities(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		amp := strings.IndexByte(s, '&')
+		if amp < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+
+		b.WriteString(s[:amp])
+		s = s[amp:]
+
+		semi := strings.IndexByte(s, ';')
+		if semi < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+
+		if decoded, ok := decodeEntity(s[1:semi]); ok {
+			b.WriteString(decoded)
+			s = s[semi+1:]
+			continue
+		}
+
+		b.WriteByte('&')
+		s = s[1:]
+	}
+}
+
+func decodeEntity(name string) (string, bool) {
+	if v, ok := xmlEntities[name]; ok {
+		return v, true
+	}
+
+	if v, ok := xml.HTMLEntity[name]; ok {
+		return v, true
+	}
+
+	if num, ok := strings.CutPrefix(name, "#"); ok {
+		base := 10
+		if hex, ok := strings.CutPrefix(num, "x"); ok {
+			num, base = hex, 16
+		}
+
+		r, err := strconv.ParseUint(num, base, 32)
+		if err != nil || r == 0 || r > 0x10FFFF {
+			return "", false
+		}
+
+		return string(rune(r)), true
+	}
+
+	return "", false
+}